@@ -0,0 +1,73 @@
+package main
+
+import "os"
+import "fmt"
+import "flag"
+import "errors"
+import "net/http"
+import "path/filepath"
+
+import "github.com/tinne26/luckyfeet/src/game/leaderboard"
+import "github.com/tinne26/luckyfeet/src/game/material/characters"
+import "github.com/tinne26/luckyfeet/src/game/material/level"
+import "github.com/tinne26/luckyfeet/src/game/race"
+import "github.com/tinne26/luckyfeet/src/game/replay"
+
+// Reference leaderboard server, verifying runs with the headless
+// simulation:
+// > go build -tags headless ./cmd/leaderboard
+// > leaderboard [-addr :8426] [-root .] [-packs levels/packs] [-data leaderboard.json]
+//
+// Built-in levels are always available. Other level packs can be
+// added as files in the packs directory, one pack per file.
+
+func main() {
+	addr := flag.String("addr", ":8426", "address to listen on")
+	root := flag.String("root", ".", "game root directory, where the assets folder is")
+	packsDir := flag.String("packs", "", "directory with extra level pack files")
+	dataPath := flag.String("data", "leaderboard.json", "file where entries are saved")
+	flag.Parse()
+
+	err := run(*addr, *root, *packsDir, *dataPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(addr, root, packsDir, dataPath string) error {
+	descs, err := characters.LoadDescriptors(os.DirFS(root))
+	if err != nil { return err }
+	packs, err := loadExtraPacks(packsDir)
+	if err != nil { return err }
+
+	server := leaderboard.NewServer(func(run *replay.Replay) (int, error) {
+		packData, found := packs[run.PackHash]
+		if !found {
+			key, builtIn := level.FindPack(run.PackHash)
+			if !builtIn { return 0, errors.New("level pack not available on this server") }
+			packData = level.GetData(key)
+		}
+		return race.Verify(run, packData, descs)
+	})
+	err = server.UseFile(dataPath)
+	if err != nil { return err }
+
+	fmt.Printf("Leaderboard listening on %s (%d extra level packs)\n", addr, len(packs))
+	return http.ListenAndServe(addr, server)
+}
+
+// Returns the data of the level packs in the given directory, by hash.
+func loadExtraPacks(dir string) (map[uint64]string, error) {
+	packs := make(map[uint64]string)
+	if dir == "" { return packs, nil }
+	entries, err := os.ReadDir(dir)
+	if err != nil { return nil, err }
+	for _, entry := range entries {
+		if entry.IsDir() { continue }
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil { return nil, err }
+		packs[level.PackHash(string(data))] = string(data)
+	}
+	return packs, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import "os"
+import "fmt"
+import "flag"
+import "errors"
+
+import "github.com/tinne26/luckyfeet/src/game/material/characters"
+import "github.com/tinne26/luckyfeet/src/game/material/level"
+import "github.com/tinne26/luckyfeet/src/game/material/version"
+import "github.com/tinne26/luckyfeet/src/game/race"
+import "github.com/tinne26/luckyfeet/src/game/replay"
+import "github.com/tinne26/luckyfeet/src/game/utils"
+
+// Replay verification without window or audio, for leaderboards:
+// > go build -tags headless ./cmd/verify
+// > verify [-pack levels/pack.txt] [-root .] replays/run.lfr
+//
+// Exit code is 0 if the run is valid, 1 if it isn't and 2 if it
+// couldn't be verified at all.
+
+func main() {
+	packPath := flag.String("pack", "", "level pack file (built-in packs are found automatically)")
+	root := flag.String("root", ".", "game root directory, where the assets folder is")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: verify [flags] replay.lfr\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	valid, err := verify(flag.Arg(0), *packPath, *root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(2)
+	}
+	if !valid { os.Exit(1) }
+}
+
+func verify(replayPath, packPath, root string) (bool, error) {
+	run, err := replay.LoadFile(replayPath)
+	if err != nil { return false, err }
+	if run.GameVersion != version.Game {
+		fmt.Printf("warning: replay from game version %s, verifying with %s\n", run.GameVersion, version.Game)
+	}
+	packData, err := loadPackData(run.PackHash, packPath)
+	if err != nil { return false, err }
+	descs, err := characters.LoadDescriptors(os.DirFS(root))
+	if err != nil { return false, err }
+
+	clearTicks, err := race.Verify(run, packData, descs)
+	if clearTicks > 0 {
+		fmt.Printf("clear: %d ticks (%s)\n", clearTicks, utils.FmtTicksToTimeStrCents(clearTicks))
+	}
+	if race.IsVerifyFailure(err) {
+		fmt.Printf("FAIL: %s\n", err)
+		return false, nil
+	}
+	if err != nil { return false, err }
+	fmt.Println("PASS")
+	return true, nil
+}
+
+// Returns the pack data from the given file, or from the built-in
+// levels if no file is given.
+func loadPackData(hash uint64, packPath string) (string, error) {
+	if packPath != "" {
+		data, err := os.ReadFile(packPath)
+		if err != nil { return "", err }
+		return string(data), nil
+	}
+	key, found := level.FindPack(hash)
+	if !found { return "", errors.New("replay level pack is not built-in, use -pack") }
+	return level.GetData(key), nil
+}
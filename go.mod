@@ -2,16 +2,19 @@ module github.com/tinne26/luckyfeet
 
 go 1.21.1
 
+require (
+	github.com/hajimehoshi/ebiten/v2 v2.6.3
+	github.com/tinne26/edau v0.0.0-20230817082835-6fc968cced2e
+	golang.design/x/clipboard v0.7.0
+)
+
 require (
 	github.com/ebitengine/oto/v3 v3.1.0 // indirect
 	github.com/ebitengine/purego v0.5.0 // indirect
-	github.com/hajimehoshi/ebiten/v2 v2.6.3 // indirect
 	github.com/hajimehoshi/go-mp3 v0.3.4 // indirect
 	github.com/jezek/xgb v1.1.0 // indirect
 	github.com/jfreymuth/oggvorbis v1.0.5 // indirect
 	github.com/jfreymuth/vorbis v1.0.2 // indirect
-	github.com/tinne26/edau v0.0.0-20230817082835-6fc968cced2e // indirect
-	golang.design/x/clipboard v0.7.0 // indirect
 	golang.org/x/exp/shiny v0.0.0-20230817173708-d852ddb80c63 // indirect
 	golang.org/x/image v0.12.0 // indirect
 	golang.org/x/mobile v0.0.0-20230922142353-e2f452493d57 // indirect
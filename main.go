@@ -1,6 +1,8 @@
 package main
 
+import "os"
 import "embed"
+import "strings"
 
 import "github.com/hajimehoshi/ebiten/v2"
 
@@ -28,9 +30,24 @@ func main() {
 
 	adapter, err := game.New(filesys)
 	if err != nil { panic(err) }
+	var leaderboardURL, playerName string = "", "ANONYMOUS"
+	for _, arg := range os.Args { // --replay=path/to/file.lfr
+		if path, found := strings.CutPrefix(arg, "--replay="); found {
+			err = adapter.LoadReplay(path)
+			if err != nil { panic(err) }
+		} else if url, found := strings.CutPrefix(arg, "--leaderboard="); found {
+			leaderboardURL = url // e.g. http://localhost:8426
+		} else if name, found := strings.CutPrefix(arg, "--name="); found {
+			playerName = name
+		}
+	}
+	if leaderboardURL != "" {
+		err = adapter.SetLeaderboard(leaderboardURL, playerName)
+		if err != nil { panic(err) }
+	}
 	ebiten.SetScreenClearedEveryFrame(false)
 	ebiten.SetCursorMode(ebiten.CursorModeHidden)
-	ebiten.SetTPS(120) // had to hack this at the end due to poor jump/tic-tac key detection
+	ebiten.SetTPS(120) // physics values are tuned per tick at this rate, jumps are buffered by the physics
 	ebiten.SetWindowTitle("Lucky Feet") // \U0001F407
 	err = ebiten.RunGame(adapter)
 	if err != nil { panic(err) }
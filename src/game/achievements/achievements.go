@@ -0,0 +1,173 @@
+package achievements
+
+import "sort"
+import "bufio"
+import "bytes"
+import "errors"
+import "strings"
+import "io/fs"
+
+import "github.com/tinne26/luckyfeet/src/game/material/level"
+import "github.com/tinne26/luckyfeet/src/game/stats"
+import "github.com/tinne26/luckyfeet/src/game/storage"
+
+// Storage name for the unlocked achievements.
+const FileName = "achievements.txt"
+
+const header = "luckyfeet achievements v1"
+
+var ErrInvalidData = errors.New("invalid achievements data")
+
+// IDs are persisted, so they must never change once released.
+type ID string
+
+type Achievement struct {
+	ID ID
+	Name string // shown on toasts and lists, only with available glyphs
+	Description string // how to unlock it, up to 40 characters
+	Hidden bool // name not listed until unlocked
+	Condition func(*Progress) bool
+}
+
+// What achievement conditions are evaluated from. Checked while
+// racing and once more when a race is cleared.
+type Progress struct {
+	Run *stats.Run // current run
+	Lifetime *stats.Lifetime // totals, not including the current run
+	PackHash uint64
+	StartMap uint8
+	Cleared bool // only set for valid clears (no replays, no tuned physics)
+	ClearTicks int
+}
+
+// Returns the built-in level that was cleared, if any. Clears
+// starting from other maps are playtests and don't count.
+func (self *Progress) ClearedLevel() (level.Key, bool) {
+	if !self.Cleared || self.StartMap != 0 { return 0, false }
+	return level.FindPack(self.PackHash)
+}
+
+func (self *Progress) TicTacs() int {
+	return self.Lifetime.TicTacs + self.Run.TicTacs
+}
+
+// All the achievements, in listing order.
+var All = []*Achievement{
+	clearLevel("CLEAR_GUIDANCE", "GUIDED", "CLEAR GUIDANCE", level.Guidance, false),
+	clearLevel("CLEAR_FIRST_RACE", "FIRST RACE DONE", "CLEAR FIRST RACE", level.FirstRace, false),
+	clearLevel("CLEAR_BUNNY", "BUNNY FOUND", "CLEAR THE SECRET LEVEL", level.Bunny, true),
+	{
+		ID: "NO_CARROTS",
+		Name: "CARROT FASTING",
+		Description: "CLEAR A LEVEL WITHOUT EATING CARROTS",
+		Condition: func(progress *Progress) bool {
+			_, builtIn := progress.ClearedLevel()
+			return builtIn && progress.Run.Eaten == [3]int{}
+		},
+	},
+	{
+		ID: "UNDER_PAR",
+		Name: "UNDER PAR",
+		Description: "CLEAR A LEVEL UNDER ITS PAR TIME",
+		Condition: func(progress *Progress) bool {
+			key, builtIn := progress.ClearedLevel()
+			return builtIn && progress.ClearTicks < level.ParTicks(key)
+		},
+	},
+	{
+		ID: "TICTACS_100",
+		Name: "TIC-TAC MACHINE",
+		Description: "PERFORM 100 TIC-TACS",
+		Condition: func(progress *Progress) bool {
+			return progress.TicTacs() >= 100
+		},
+	},
+}
+
+func clearLevel(id ID, name, description string, key level.Key, hidden bool) *Achievement {
+	return &Achievement{
+		ID: id,
+		Name: name,
+		Description: description,
+		Hidden: hidden,
+		Condition: func(progress *Progress) bool {
+			cleared, builtIn := progress.ClearedLevel()
+			return builtIn && cleared == key
+		},
+	}
+}
+
+// Set of unlocked achievement IDs. Unknown IDs are kept, so
+// achievements from newer versions aren't lost on save.
+type Unlocked struct {
+	ids map[ID]bool
+}
+
+func New() *Unlocked {
+	return &Unlocked{ ids: make(map[ID]bool) }
+}
+
+func (self *Unlocked) Has(id ID) bool { return self.ids[id] }
+
+// Returns how many of the known achievements are unlocked.
+func (self *Unlocked) Count() int {
+	var count int
+	for _, achievement := range All {
+		if self.ids[achievement.ID] { count += 1 }
+	}
+	return count
+}
+
+// Evaluates the locked achievements and returns the ones that
+// got unlocked, if any.
+func (self *Unlocked) Check(progress *Progress) []*Achievement {
+	var unlocked []*Achievement
+	for _, achievement := range All {
+		if self.ids[achievement.ID] || !achievement.Condition(progress) { continue }
+		self.ids[achievement.ID] = true
+		unlocked = append(unlocked, achievement)
+	}
+	return unlocked
+}
+
+// Loads the unlocked achievements from storage. If nothing was
+// saved yet, an empty set is returned.
+func Load() (*Unlocked, error) {
+	data, err := storage.Load(FileName)
+	if errors.Is(err, fs.ErrNotExist) { return New(), nil }
+	if err != nil { return nil, err }
+	return Decode(data)
+}
+
+func (self *Unlocked) Save() error {
+	return storage.Save(FileName, self.Encode())
+}
+
+// Encodes the unlocked achievements as text, one ID per line.
+func (self *Unlocked) Encode() []byte {
+	ids := make([]string, 0, len(self.ids))
+	for id, _ := range self.ids { ids = append(ids, string(id)) }
+	sort.Strings(ids)
+
+	var buffer bytes.Buffer
+	buffer.WriteString(header + "\n")
+	for _, id := range ids {
+		buffer.WriteString(id + "\n")
+	}
+	return buffer.Bytes()
+}
+
+func Decode(data []byte) (*Unlocked, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() || scanner.Text() != header { return nil, ErrInvalidData }
+
+	unlocked := New()
+	for scanner.Scan() {
+		id := strings.TrimSpace(scanner.Text())
+		if id == "" { continue }
+		if strings.ContainsAny(id, " \t") { return nil, ErrInvalidData }
+		unlocked.ids[ID(id)] = true
+	}
+	if scanner.Err() != nil { return nil, ErrInvalidData }
+	return unlocked, nil
+}
@@ -0,0 +1,65 @@
+package achievements
+
+import "testing"
+
+import "github.com/tinne26/luckyfeet/src/game/material/level"
+import "github.com/tinne26/luckyfeet/src/game/stats"
+
+func TestCheck(t *testing.T) {
+	unlocked := New()
+	progress := &Progress{ Run: stats.NewRun(4), Lifetime: stats.NewLifetime() }
+	progress.Lifetime.TicTacs = 98
+	progress.Run.TicTacs = 1
+	if len(unlocked.Check(progress)) != 0 { t.Fatal("unexpected unlocks") }
+
+	// tic-tacs count across runs
+	progress.Run.TicTacs = 2
+	expectUnlocks(t, unlocked.Check(progress), "TICTACS_100")
+	if len(unlocked.Check(progress)) != 0 { t.Fatal("unexpected repeated unlocks") }
+
+	// clears over par, with a carrot eaten
+	progress.PackHash = level.PackHash(level.GetData(level.FirstRace))
+	progress.Cleared = true
+	progress.ClearTicks = level.ParTicks(level.FirstRace)
+	progress.Run.Eat(2)
+	expectUnlocks(t, unlocked.Check(progress), "CLEAR_FIRST_RACE")
+
+	// playtests from other maps don't count
+	progress.PackHash = level.PackHash(level.GetData(level.Guidance))
+	progress.StartMap = 1
+	progress.ClearTicks = 10
+	progress.Run = stats.NewRun(4)
+	if len(unlocked.Check(progress)) != 0 { t.Fatal("unexpected unlocks on playtest") }
+
+	progress.StartMap = 0
+	expectUnlocks(t, unlocked.Check(progress), "CLEAR_GUIDANCE", "NO_CARROTS", "UNDER_PAR")
+	if unlocked.Count() != 5 { t.Fatalf("expected 5 unlocked, got %d", unlocked.Count()) }
+}
+
+func TestEncode(t *testing.T) {
+	unlocked, err := Decode([]byte(header + "\nUNDER_PAR\n\nFROM_THE_FUTURE\n"))
+	if err != nil { t.Fatal(err) }
+	if !unlocked.Has("UNDER_PAR") || unlocked.Count() != 1 {
+		t.Fatalf("unexpected unlocks %v", unlocked.ids)
+	}
+
+	decoded, err := Decode(unlocked.Encode())
+	if err != nil { t.Fatal(err) }
+	if !decoded.Has("UNDER_PAR") || !decoded.Has("FROM_THE_FUTURE") || len(decoded.ids) != 2 {
+		t.Fatalf("unexpected decoded unlocks %v", decoded.ids)
+	}
+
+	invalid := []string{ "", "luckyfeet achievements v0\n", header + "\nUNDER PAR\n" }
+	for _, data := range invalid {
+		_, err := Decode([]byte(data))
+		if err == nil { t.Fatalf("expected error for %q", data) }
+	}
+}
+
+func expectUnlocks(t *testing.T, unlocked []*Achievement, ids ...ID) {
+	t.Helper()
+	if len(unlocked) != len(ids) { t.Fatalf("expected %v, got %d unlocks", ids, len(unlocked)) }
+	for i, achievement := range unlocked {
+		if achievement.ID != ids[i] { t.Fatalf("expected %v, got %s at %d", ids, achievement.ID, i) }
+	}
+}
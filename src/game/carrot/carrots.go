@@ -1,13 +1,7 @@
 package carrot
 
-import "image/color"
-
-import "github.com/hajimehoshi/ebiten/v2"
-
 import "github.com/tinne26/luckyfeet/src/game/utils"
-import "github.com/tinne26/luckyfeet/src/game/context"
-import "github.com/tinne26/luckyfeet/src/game/material/in"
-import "github.com/tinne26/luckyfeet/src/game/material/au"
+import "github.com/tinne26/luckyfeet/src/game/replay"
 
 type Variety uint8
 const (
@@ -24,6 +18,16 @@ type Carrot struct {
 	OriginCol uint8
 }
 
+// Inventory actions triggered by input, so feedback can be
+// given without the inventory knowing about audio. A single
+// update can trigger multiple actions.
+type Actions uint8
+const (
+	Switched Actions = 1 << iota
+	Consumed
+	ConsumeFailed
+)
+
 const carrotsCapacity = 3
 type Inventory struct {
 	Carrots [carrotsCapacity]Carrot
@@ -44,7 +48,8 @@ func (self *Inventory) RemoveAll() {
 }
 
 var unfillSpeeds [numVarieties]float64 = [numVarieties]float64{0.0, 0.002, 0.004, 0.007}
-func (self *Inventory) Update(ctx *context.Context) {
+func (self *Inventory) Update(tick replay.Tick) Actions {
+	var actions Actions
 	self.SelectorOpacityBlinker.Update()
 	for i, _ := range self.Carrots {
 		variety := self.Carrots[i].Variety
@@ -58,24 +63,29 @@ func (self *Inventory) Update(ctx *context.Context) {
 		}
 	}
 
-	if ctx.Input.Trigger(in.ActionPrevCarrot) {
+	if tick.Has(replay.PrevCarrot) {
 		if self.ActiveIndex == 0 {
 			self.ActiveIndex = carrotsCapacity - 1
 		} else {
 			self.ActiveIndex -= 1
 		}
-		ctx.Audio.PlaySFX(au.SfxClick)
-	} else if ctx.Input.Trigger(in.ActionNextCarrot) {
+		actions |= Switched
+	} else if tick.Has(replay.NextCarrot) {
 		self.ActiveIndex += 1
 		if self.ActiveIndex >= carrotsCapacity {
 			self.ActiveIndex = 0
 		}
-		ctx.Audio.PlaySFX(au.SfxClick)
+		actions |= Switched
 	}
 
-	if ctx.Input.Trigger(in.ActionUseCarrot) {
-		_ = self.TryConsume(ctx)
+	if tick.Has(replay.UseCarrot) {
+		if self.TryConsume() {
+			actions |= Consumed
+		} else {
+			actions |= ConsumeFailed
+		}
 	}
+	return actions
 }
 
 // Necessary to draw the carrot platform fills.
@@ -109,17 +119,15 @@ func (self *Inventory) IsMapCarrotOn(col, row uint8) bool {
 	return true
 }
 
-func (self *Inventory) TryConsume(ctx *context.Context) bool {
+func (self *Inventory) TryConsume() bool {
 	if self.Carrots[self.ActiveIndex].Variety == None || self.FillLevels[self.ActiveIndex] < 1.0 {
-		ctx.Audio.PlaySFX(au.SfxScratch)
 		return false
 	}
-	ctx.Audio.PlaySFX(au.SfxCronch)
 	self.FillLevels[self.ActiveIndex] = 0.9999
 	return true
 }
 
-func (self *Inventory) TryAdd(ctx *context.Context, carrot Carrot) bool {
+func (self *Inventory) TryAdd(carrot Carrot) bool {
 	if carrot.Variety == None { panic("precondition violation") }
 
 	for i, _ := range self.Carrots {
@@ -133,47 +141,3 @@ func (self *Inventory) TryAdd(ctx *context.Context, carrot Carrot) bool {
 
 	return false
 }
-
-func (self *Inventory) Draw(canvas *ebiten.Image, ctx *context.Context) {
-	bounds := ctx.Gfxcore.CarrotSelector.Bounds()
-	const pad = 3
-	x := float64(640 - 6 - bounds.Dx()*carrotsCapacity - pad*(carrotsCapacity - 1))
-	y := float64(360 - 6 - bounds.Dy())
-	// x := float64(640 - bounds.Dx()*carrotsCapacity - pad*(carrotsCapacity - 1))/2.0
-	// y := float64(6)
-	
-	var opts ebiten.DrawImageOptions
-	opts.GeoM.Translate(x, y)
-	for i, _ := range self.Carrots {
-		// draw selector if on active index
-		if i == int(self.ActiveIndex) {
-			a := float32(self.SelectorOpacityBlinker.Value())
-			opts.ColorScale.Scale(a, a, a, a)
-			canvas.DrawImage(ctx.Gfxcore.CarrotSelector, &opts)
-			opts.ColorScale.Reset()
-		}
-
-		// draw carrot mask
-		canvas.DrawImage(ctx.Gfxcore.CarrotNone, &opts)
-		variety := self.Carrots[i].Variety
-		if variety != None {
-			a := float32(self.FillLevels[i])
-			if a < 1.0 {
-				opts.ColorScale.ScaleWithColor(color.RGBA{230, 30, 230, 255})
-				canvas.DrawImage(ctx.Gfxcore.CarrotInUseMask, &opts)
-				opts.ColorScale.Reset()
-			}
-
-			opts.ColorScale.Scale(a, a, a, a)
-			switch variety {
-			case Orange: canvas.DrawImage(ctx.Gfxcore.CarrotOrange, &opts)
-			case Yellow: canvas.DrawImage(ctx.Gfxcore.CarrotYellow, &opts)
-			case Purple: canvas.DrawImage(ctx.Gfxcore.CarrotPurple, &opts)
-			default: panic("broken code")
-			}
-			opts.ColorScale.Reset()
-		}
-
-		opts.GeoM.Translate(float64(bounds.Dx()) + pad, 0)
-	}
-}
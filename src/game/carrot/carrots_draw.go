@@ -0,0 +1,53 @@
+//go:build !headless
+
+package carrot
+
+import "image/color"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+
+func (self *Inventory) Draw(canvas *ebiten.Image, ctx *context.Context) {
+	bounds := ctx.Gfxcore.CarrotSelector.Bounds()
+	const pad = 3
+	x := float64(640 - 6 - bounds.Dx()*carrotsCapacity - pad*(carrotsCapacity - 1))
+	y := float64(360 - 6 - bounds.Dy())
+	// x := float64(640 - bounds.Dx()*carrotsCapacity - pad*(carrotsCapacity - 1))/2.0
+	// y := float64(6)
+	
+	var opts ebiten.DrawImageOptions
+	opts.GeoM.Translate(x, y)
+	for i, _ := range self.Carrots {
+		// draw selector if on active index
+		if i == int(self.ActiveIndex) {
+			a := float32(self.SelectorOpacityBlinker.Value())
+			opts.ColorScale.Scale(a, a, a, a)
+			canvas.DrawImage(ctx.Gfxcore.CarrotSelector, &opts)
+			opts.ColorScale.Reset()
+		}
+
+		// draw carrot mask
+		canvas.DrawImage(ctx.Gfxcore.CarrotNone, &opts)
+		variety := self.Carrots[i].Variety
+		if variety != None {
+			a := float32(self.FillLevels[i])
+			if a < 1.0 {
+				opts.ColorScale.ScaleWithColor(color.RGBA{230, 30, 230, 255})
+				canvas.DrawImage(ctx.Gfxcore.CarrotInUseMask, &opts)
+				opts.ColorScale.Reset()
+			}
+
+			opts.ColorScale.Scale(a, a, a, a)
+			switch variety {
+			case Orange: canvas.DrawImage(ctx.Gfxcore.CarrotOrange, &opts)
+			case Yellow: canvas.DrawImage(ctx.Gfxcore.CarrotYellow, &opts)
+			case Purple: canvas.DrawImage(ctx.Gfxcore.CarrotPurple, &opts)
+			default: panic("broken code")
+			}
+			opts.ColorScale.Reset()
+		}
+
+		opts.GeoM.Translate(float64(bounds.Dx()) + pad, 0)
+	}
+}
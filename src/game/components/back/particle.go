@@ -19,6 +19,19 @@ type Particle struct {
 }
 
 func (self *Particle) Update() {
+	if self.Advance() { return }
+	self.X = rand.Float64()*640
+	self.Y = rand.Float64()*360
+	self.Speed = 0.02 + rand.Float64()*0.05
+	self.Dir = uint8(rand.Intn(4))
+	self.LifeTicksLeft = 60 + uint16(rand.Intn(160))
+	self.TransitionTicks = 30 + uint16(rand.Intn(90))
+	self.TransitionElapsed = 0
+}
+
+// Updates the particle movement and life. Returns false when the
+// particle has completely faded out and needs to be rerolled.
+func (self *Particle) Advance() bool {
 	// update movement
 	switch self.Dir {
 	case 0: // NE
@@ -51,17 +64,7 @@ func (self *Particle) Update() {
 		self.TransitionElapsed += 1
 	}
 
-	// if dead, reroll
-	if self.LifeTicksLeft > 0 || self.TransitionElapsed < self.TransitionTicks {
-		return
-	}
-	self.X = rand.Float64()*640
-	self.Y = rand.Float64()*360
-	self.Speed = 0.02 + rand.Float64()*0.05
-	self.Dir = uint8(rand.Intn(4))
-	self.LifeTicksLeft = 60 + uint16(rand.Intn(160))
-	self.TransitionTicks = 30 + uint16(rand.Intn(90))
-	self.TransitionElapsed = 0
+	return self.LifeTicksLeft > 0 || self.TransitionElapsed < self.TransitionTicks
 }
 
 func (self *Particle) DrawLogical(canvas *ebiten.Image, ctx *context.Context, opts *ebiten.DrawImageOptions) {
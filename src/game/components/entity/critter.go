@@ -0,0 +1,100 @@
+package entity
+
+import "image"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile"
+import "github.com/tinne26/luckyfeet/src/game/carrot"
+
+const (
+	Gravity = 0.12
+	MaxFallSpeed = 3.0
+
+	// critter sprites are 12x10 frames in gfxcore.Critters, one
+	// row per critter type, with a 10x7 collision rect at (1, 3)
+	spriteWidth, spriteHeight = 12, 10
+	critterWidth, critterHeight = 10, 7
+	critterOffsetX, critterOffsetY = 1, 3
+)
+
+// Shared motion for simple critters: gravity, landing on main
+// layer ground and turning around at walls and edges. Critters
+// can't walk slopes, they treat them like walls.
+type critter struct {
+	x, y float64 // top-left corner of the collision rect
+	speedY float64
+	dir int // +1 right, -1 left
+	grounded bool
+}
+
+func newCritter(spawnPoint tile.Tile) critter {
+	dir := +1
+	if spawnPoint.Orientation.IsMirrored() { dir = -1 }
+
+	// like the player, critters start at grass level
+	x := int(spawnPoint.Column)*20 + (20 - critterWidth)/2
+	y := int(spawnPoint.Row)*20 + 11 - critterHeight
+	return critter{ x: float64(x), y: float64(y), dir: dir }
+}
+
+func (self *critter) Rect() image.Rectangle {
+	return self.rectAt(self.x, self.y)
+}
+
+func (self *critter) HasFallen() bool {
+	return self.y >= 360
+}
+
+func (self *critter) rectAt(x, y float64) image.Rectangle {
+	ix, iy := int(x), int(y)
+	return image.Rect(ix, iy, ix + critterWidth, iy + critterHeight)
+}
+
+// Reports whether the critter can't be at the given x position.
+func (self *critter) blockedAt(carrots *carrot.Inventory, tilemap *tile.Map, x float64) bool {
+	if x < 0 || x + critterWidth > 640 { return true }
+	return tilemap.Collides(carrots, self.rectAt(x, self.y), Layer)
+}
+
+// Reports whether the front foot of the critter would still be
+// on the ground at the given x position.
+func (self *critter) hasGroundAt(carrots *carrot.Inventory, tilemap *tile.Map, x float64) bool {
+	footX := int(x)
+	if self.dir > 0 { footX += critterWidth - 1 }
+	return tilemap.HasLandingFor(carrots, footX, footX, int(self.y) + critterHeight, Layer)
+}
+
+func (self *critter) landsAt(carrots *carrot.Inventory, tilemap *tile.Map, iy int) bool {
+	ix := int(self.x)
+	return tilemap.HasLandingFor(carrots, ix, ix + critterWidth - 1, iy + critterHeight, Layer)
+}
+
+// Applies gravity and vertical motion. Critters stop at ceilings
+// and land on any main layer ground.
+func (self *critter) fall(carrots *carrot.Inventory, tilemap *tile.Map) {
+	if self.grounded {
+		if self.landsAt(carrots, tilemap, int(self.y)) { return }
+		self.grounded = false // carrot platforms can vanish
+	}
+
+	self.speedY = min(self.speedY + Gravity, MaxFallSpeed)
+	if self.speedY < 0 {
+		y := self.y + self.speedY
+		if tilemap.Collides(carrots, self.rectAt(self.x, y), Layer) {
+			self.speedY = 0
+		} else {
+			self.y = y
+		}
+		return
+	}
+
+	// check each pixel on the way down so we never skip a landing
+	y := self.y + self.speedY
+	for iy := int(self.y); iy <= int(y); iy++ {
+		if self.landsAt(carrots, tilemap, iy) {
+			self.y, self.speedY = float64(iy), 0
+			self.grounded = true
+			return
+		}
+	}
+	self.y = y
+}
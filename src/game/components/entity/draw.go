@@ -0,0 +1,49 @@
+//go:build !headless
+
+package entity
+
+import "image"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+
+type drawer interface {
+	Draw(canvas *ebiten.Image, ctx *context.Context)
+}
+
+var critterDrawOpts ebiten.DrawImageOptions
+
+func (self *Actors) DrawLogical(canvas *ebiten.Image, ctx *context.Context) {
+	for _, actor := range self.actors {
+		actor.Draw(canvas, ctx)
+	}
+}
+
+func (self *patroller) Draw(canvas *ebiten.Image, ctx *context.Context) {
+	self.drawFrame(canvas, ctx, (self.walkTicks/12) & 1, 0)
+}
+
+func (self *hopper) Draw(canvas *ebiten.Image, ctx *context.Context) {
+	switch {
+	case !self.grounded:
+		self.drawFrame(canvas, ctx, 2, 1)
+	case self.waitTicks >= HopperWaitTicks - HopperCrouchTicks:
+		self.drawFrame(canvas, ctx, 1, 1)
+	default:
+		self.drawFrame(canvas, ctx, 0, 1)
+	}
+}
+
+func (self *critter) drawFrame(canvas *ebiten.Image, ctx *context.Context, col, row int) {
+	ox, oy := col*spriteWidth, row*spriteHeight
+	frame := ctx.Gfxcore.Critters.SubImage(image.Rect(ox, oy, ox + spriteWidth, oy + spriteHeight)).(*ebiten.Image)
+	if self.dir < 0 {
+		critterDrawOpts.GeoM.Scale(-1, 1)
+		critterDrawOpts.GeoM.Translate(spriteWidth, 0)
+	}
+	ix, iy := int(self.x), int(self.y)
+	critterDrawOpts.GeoM.Translate(float64(ix - critterOffsetX), float64(iy - critterOffsetY))
+	canvas.DrawImage(frame, &critterDrawOpts)
+	critterDrawOpts.GeoM.Reset()
+}
@@ -0,0 +1,73 @@
+package entity
+
+import "image"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile"
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+import "github.com/tinne26/luckyfeet/src/game/carrot"
+
+// Entities live on the main layer. They are drawn right after the
+// main tiles, so players behind the main layer are hidden by both.
+const Layer = tcsts.LayerMain
+
+// Non-tile actors with their own position and logic. Actors are
+// spawned from the map's entity layer, see [Actors.Respawn]().
+// Drawing is excluded from headless builds, see draw.go.
+type Actor interface {
+	Update(carrots *carrot.Inventory, tilemap *tile.Map)
+	Rect() image.Rectangle // collision rect, in logical coordinates
+	HasFallen() bool
+	drawer
+}
+
+// Creates the actor for the given entity layer tile.
+func Spawn(spawnPoint tile.Tile) Actor {
+	switch spawnPoint.ID {
+	case tcsts.EntityPatroller: return newPatroller(spawnPoint)
+	case tcsts.EntityHopper   : return newHopper(spawnPoint)
+	default:
+		panic("broken code")
+	}
+}
+
+// The active actors of a map.
+type Actors struct {
+	actors []Actor
+}
+
+// Recreates all actors from their spawn points. Must be called
+// whenever the player respawns or the active map changes, so
+// each attempt at a map plays out the same way.
+func (self *Actors) Respawn(tilemap *tile.Map) {
+	clear(self.actors)
+	self.actors = self.actors[ : 0]
+	for _, spawnPoint := range tilemap.Layers[tcsts.LayerEntities] {
+		self.actors = append(self.actors, Spawn(spawnPoint))
+	}
+}
+
+func (self *Actors) Update(carrots *carrot.Inventory, tilemap *tile.Map) {
+	var removed int
+	for i, actor := range self.actors {
+		actor.Update(carrots, tilemap)
+		if actor.HasFallen() {
+			removed += 1
+		} else {
+			self.actors[i - removed] = actor
+		}
+	}
+	clear(self.actors[len(self.actors) - removed : ])
+	self.actors = self.actors[ : len(self.actors) - removed]
+}
+
+// Reports whether the given rect on the given layer touches
+// any actor.
+func (self *Actors) Touches(rect image.Rectangle, layer int) bool {
+	if layer != Layer { return false }
+	for _, actor := range self.actors {
+		if actor.Rect().Overlaps(rect) { return true }
+	}
+	return false
+}
+
+func (self *Actors) Len() int { return len(self.actors) }
@@ -0,0 +1,74 @@
+package entity
+
+import "testing"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile"
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+import "github.com/tinne26/luckyfeet/src/game/carrot"
+
+// Grass from column 2 to 6 on row 10, with a ground wall on
+// column 7 and a spawn point on each end.
+func testMap(spawnID uint8) *tile.Map {
+	tilemap := tile.NewMap(1)
+	for col := uint8(2); col <= 6; col++ {
+		tilemap.SetTile(tile.Tile{ ID: tcsts.MainGrassSide, Row: 10, Column: col }, tcsts.LayerMain)
+	}
+	tilemap.SetTile(tile.Tile{ ID: tcsts.MainGround, Row: 10, Column: 7 }, tcsts.LayerMain)
+	tilemap.SetTile(tile.Tile{ ID: spawnID, Row: 9, Column: 3 }, tcsts.LayerEntities)
+	mirrored := tile.Orientation(0).Mirrored()
+	tilemap.SetTile(tile.Tile{ ID: spawnID, Row: 10, Column: 5, Orientation: mirrored }, tcsts.LayerEntities)
+	return tilemap
+}
+
+func runActors(t *testing.T, tilemap *tile.Map, ticks int) *Actors {
+	var actors Actors
+	actors.Respawn(tilemap)
+	carrots := &carrot.Inventory{}
+	for tick := 0; tick < ticks; tick++ {
+		actors.Update(carrots, tilemap)
+		for i, actor := range actors.actors {
+			rect := actor.Rect()
+			if rect.Min.X < 40 || rect.Max.X > 140 || rect.Max.Y > 211 {
+				t.Fatalf("tick %d, actor #%d out of its grass patch: %v", tick, i, rect)
+			}
+		}
+	}
+	return &actors
+}
+
+func TestPatrollers(t *testing.T) {
+	actors := runActors(t, testMap(tcsts.EntityPatroller), 2000)
+	if actors.Len() != 2 { t.Fatalf("expected 2 patrollers, got %d", actors.Len()) }
+
+	// both patrollers must have turned around at least once
+	for i, actor := range actors.actors {
+		if actor.(*patroller).walkTicks < 600 {
+			t.Fatalf("patroller #%d barely walked", i)
+		}
+	}
+}
+
+func TestHoppers(t *testing.T) {
+	actors := runActors(t, testMap(tcsts.EntityHopper), 2000)
+	if actors.Len() != 2 { t.Fatalf("expected 2 hoppers, got %d", actors.Len()) }
+}
+
+func TestFallenActorsAreRemoved(t *testing.T) {
+	tilemap := tile.NewMap(1)
+	tilemap.SetTile(tile.Tile{ ID: tcsts.EntityHopper, Row: 3, Column: 3 }, tcsts.LayerEntities)
+	var actors Actors
+	actors.Respawn(tilemap)
+	for tick := 0; tick < 300; tick++ {
+		actors.Update(&carrot.Inventory{}, tilemap)
+	}
+	if actors.Len() != 0 { t.Fatal("expected the hopper to fall off the map") }
+}
+
+func TestTouches(t *testing.T) {
+	var actors Actors
+	actors.Respawn(testMap(tcsts.EntityPatroller))
+	rect := actors.actors[0].Rect()
+	if !actors.Touches(rect, tcsts.LayerMain) { t.Fatal("expected touch on main layer") }
+	if actors.Touches(rect, tcsts.LayerBack) { t.Fatal("unexpected touch on back layer") }
+	if actors.Touches(rect.Add(rect.Size()), tcsts.LayerMain) { t.Fatal("unexpected touch") }
+}
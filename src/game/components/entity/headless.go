@@ -0,0 +1,6 @@
+//go:build headless
+
+package entity
+
+// Headless builds can't draw, see draw.go.
+type drawer interface {}
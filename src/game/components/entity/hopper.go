@@ -0,0 +1,68 @@
+package entity
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile"
+import "github.com/tinne26/luckyfeet/src/game/carrot"
+
+const (
+	HopperWaitTicks = 50
+	HopperCrouchTicks = 10 // last ticks of the wait, only visual
+	HopperJumpSpeed = -2.4
+	HopperSpeedX = 0.5
+
+	// horizontal distance covered by a hop on flat ground
+	hopperHopDist = HopperSpeedX*(2*(-HopperJumpSpeed)/Gravity)
+)
+
+// Hoppers wait on the ground and periodically hop forward,
+// turning around when the next hop wouldn't land on the same
+// ground level. If neither side works, they hop in place.
+type hopper struct {
+	critter
+	waitTicks int
+	speedX float64
+}
+
+func newHopper(spawnPoint tile.Tile) *hopper {
+	return &hopper{ critter: newCritter(spawnPoint) }
+}
+
+func (self *hopper) Update(carrots *carrot.Inventory, tilemap *tile.Map) {
+	wasGrounded := self.grounded
+	if !self.grounded {
+		x := self.x + float64(self.speedX*float64(self.dir))
+		if self.blockedAt(carrots, tilemap, x) {
+			self.speedX = 0
+		} else {
+			self.x = x
+		}
+	}
+
+	self.fall(carrots, tilemap)
+	if !self.grounded { return }
+	if !wasGrounded { self.waitTicks = 0 }
+
+	self.waitTicks += 1
+	if self.waitTicks < HopperWaitTicks { return }
+	self.hop(carrots, tilemap)
+}
+
+func (self *hopper) hop(carrots *carrot.Inventory, tilemap *tile.Map) {
+	self.speedX = HopperSpeedX
+	if !self.canHopTowards(carrots, tilemap, self.dir) {
+		self.dir = -self.dir
+		if !self.canHopTowards(carrots, tilemap, self.dir) {
+			self.speedX = 0
+		}
+	}
+	self.speedY = HopperJumpSpeed
+	self.grounded = false
+	self.waitTicks = 0
+}
+
+func (self *hopper) canHopTowards(carrots *carrot.Inventory, tilemap *tile.Map, dir int) bool {
+	x := self.x + float64(hopperHopDist*float64(dir))
+	if x < 0 || x + critterWidth > 640 { return false }
+	if tilemap.Collides(carrots, self.rectAt(x, self.y), Layer) { return false }
+	ix := int(x)
+	return tilemap.HasLandingFor(carrots, ix, ix + critterWidth - 1, int(self.y) + critterHeight, Layer)
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile"
+import "github.com/tinne26/luckyfeet/src/game/carrot"
+
+const PatrollerSpeed = 0.4
+
+// Patrollers walk back and forth, turning around at walls and
+// ground edges.
+type patroller struct {
+	critter
+	walkTicks int
+}
+
+func newPatroller(spawnPoint tile.Tile) *patroller {
+	return &patroller{ critter: newCritter(spawnPoint) }
+}
+
+func (self *patroller) Update(carrots *carrot.Inventory, tilemap *tile.Map) {
+	self.fall(carrots, tilemap)
+	if !self.grounded { return }
+
+	x := self.x + float64(PatrollerSpeed*float64(self.dir))
+	if self.blockedAt(carrots, tilemap, x) || !self.hasGroundAt(carrots, tilemap, x) {
+		self.dir = -self.dir
+		return
+	}
+	self.x = x
+	self.walkTicks += 1
+}
@@ -0,0 +1,23 @@
+package info
+
+import "fmt"
+
+import "github.com/tinne26/luckyfeet/src/game/achievements"
+
+// Returns the content for an achievements info layer. Hidden
+// achievements are only named once unlocked.
+func Achievements(unlocked *achievements.Unlocked) []string {
+	header := fmt.Sprintf("ACHIEVEMENTS (%d/%d)", unlocked.Count(), len(achievements.All))
+	lines := []string{ header, "" }
+	for _, achievement := range achievements.All {
+		switch {
+		case unlocked.Has(achievement.ID):
+			lines = append(lines, "[X] " + achievement.Name, achievement.Description)
+		case achievement.Hidden:
+			lines = append(lines, "[ ] ???", "???")
+		default:
+			lines = append(lines, "[ ] " + achievement.Name, achievement.Description)
+		}
+	}
+	return lines
+}
@@ -6,6 +6,7 @@ var ControlsKB = []string{
 	"MENU: " + string(text.KeyTAB),
 	"MOVEMENT: WASD",
 	"JUMP: SPACEBAR",
+	"DASH (IF UNLOCKED): " + string(text.KeyL),
 	"",
 	"SELECT CARROT: " + string(text.KeyI) + " AND " + string(text.KeyP),
 	"USE CARROT: " + string(text.KeyO),
@@ -15,6 +16,7 @@ var ControlsGP = []string{
 	"MOVEMENT: D-PAD",
 	"CONFIRM/JUMP: BOTTOM BUTTON " + string(text.GpBtBottom),
 	"CANCEL/BACK: RIGHT BUTTON " + string(text.GpBtRight),
+	"DASH (IF UNLOCKED): UP BUTTON " + string(text.GpBtTop),
 	"",
 	"SELECT CARROT: L/R SHOULDERS " + string(text.GpShoulders),
 	"USE CARROT: LEFT BUTTON " + string(text.GpBtLeft),
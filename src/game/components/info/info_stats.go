@@ -0,0 +1,71 @@
+package info
+
+import "fmt"
+import "strings"
+
+import "github.com/tinne26/luckyfeet/src/game/material/level"
+import "github.com/tinne26/luckyfeet/src/game/stats"
+import "github.com/tinne26/luckyfeet/src/game/utils"
+
+var builtInLevels = []struct{ key level.Key; name string }{
+	{ level.Guidance, "GUIDANCE" },
+	{ level.FirstRace, "FIRST RACE" },
+	{ level.Bunny, "BUNNY" },
+}
+
+// Returns the content for a run stats info layer.
+func RunStats(run *stats.Run) []string {
+	lines := []string{ "RUN STATS", "" }
+	lines = append(lines, countersLines(&run.Counters)...)
+	lines = append(lines, "", "TIME PER MAP")
+	for i, ticks := range run.MapTicks {
+		if ticks == 0 { continue }
+		lines = append(lines, fmt.Sprintf("MAP %d: %s", i + 1, utils.FmtTicksToTimeStrCents(ticks)))
+	}
+	return lines
+}
+
+// Returns the content for a lifetime stats info layer.
+func LifetimeStats(lifetime *stats.Lifetime) []string {
+	lines := []string{ "LIFETIME STATS", "" }
+	lines = append(lines, fmt.Sprintf("RUNS: %d    CLEARS: %d", lifetime.Runs, lifetime.Clears))
+	lines = append(lines, countersLines(&lifetime.Counters)...)
+	lines = append(lines, "", "TIME PER MAP")
+
+	// built-in levels by map, everything else together
+	otherTicks := lifetime.TotalTicks()
+	for _, builtIn := range builtInLevels {
+		packHash := level.PackHash(level.GetData(builtIn.key))
+		packTicks := lifetime.PackTicks(packHash)
+		if packTicks == 0 { continue } // also keeps secrets secret
+		otherTicks -= packTicks
+
+		numMaps := strings.Count(strings.TrimSpace(level.GetData(builtIn.key)), ".") + 1
+		mapTimes := make([]string, numMaps)
+		for i, _ := range mapTimes {
+			ticks := lifetime.MapTicks[stats.MapKey{ PackHash: packHash, MapIndex: uint8(i) }]
+			mapTimes[i] = utils.FmtTicksToTimeStrSecs(ticks)
+		}
+		lines = append(lines, builtIn.name + ": " + strings.Join(mapTimes, " / "))
+	}
+	if otherTicks > 0 {
+		lines = append(lines, "OTHER LEVELS: " + utils.FmtTicksToTimeStrSecs(otherTicks))
+	}
+	if lifetime.TotalTicks() == 0 {
+		lines = append(lines, "NOTHING YET")
+	}
+	return lines
+}
+
+func countersLines(counters *stats.Counters) []string {
+	return []string{
+		fmt.Sprintf("JUMPS: %d    TIC-TACS: %d    FALLS: %d", counters.Jumps, counters.TicTacs, counters.Falls),
+		fmt.Sprintf("MAP TRANSFERS: %d", counters.Transfers),
+		"CARROTS COLLECTED: " + carrotCounts(counters.Collected),
+		"CARROTS EATEN: " + carrotCounts(counters.Eaten),
+	}
+}
+
+func carrotCounts(counts [3]int) string {
+	return fmt.Sprintf("%d ORANGE, %d YELLOW, %d PURPLE", counts[0], counts[1], counts[2])
+}
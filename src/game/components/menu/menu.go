@@ -19,6 +19,7 @@ const (
 	OptsAudio
 	OptsWindow
 	OptsScaling
+	OptsGamepad
 	FirstKey
 )
 
@@ -49,6 +50,7 @@ func (self *Menu) Update(ctx *context.Context) (*scene.Change, error) {
 			optList.index = 0
 		}
 		self.key = key
+		ctx.SaveSettingsIfDirty() // audio changes are saved when leaving the list
 	}
 	return change, err
 }
@@ -80,6 +82,7 @@ func (self *Menu) NewGameOptionsOptionList(ctx *context.Context) {
 	opts.Add(&NavOption{ Label: "AUDIO", To: OptsAudio })
 	opts.Add(&NavOption{ Label: "WINDOW", To: OptsWindow })
 	opts.Add(&NavOption{ Label: "SCALING", To: OptsScaling })
+	opts.Add(&NavOption{ Label: "GAMEPAD", To: OptsGamepad })
 	opts.AddBackOption(&NavOption{ Label: "BACK", To: Back })
 
 	opts = self.NewOptionList(OptsAudio)
@@ -104,10 +107,12 @@ func (self *Menu) NewGameOptionsOptionList(ctx *context.Context) {
 		},
 		SetLevel: func(fnCtx *context.Context, value float32) error {
 			fnCtx.Audio.SetUserBGMVolume(value)
+			fnCtx.MarkSettingsDirty()
 			return nil
 		},
 		OnClick: func(fnCtx *context.Context) {
 			fnCtx.Audio.SetBGMMuted(!fnCtx.Audio.GetBGMMuted())
+			fnCtx.MarkSettingsDirty()
 		}})
 	opts.Add(&AudioOption{
 		BaseLabel: "SFX",
@@ -117,10 +122,12 @@ func (self *Menu) NewGameOptionsOptionList(ctx *context.Context) {
 		},
 		SetLevel: func(fnCtx *context.Context, value float32) error {
 			fnCtx.Audio.SetUserSFXVolume(value)
+			fnCtx.MarkSettingsDirty()
 			return nil
 		},
 		OnClick: func(fnCtx *context.Context) {
 			fnCtx.Audio.SetSFXMuted(!fnCtx.Audio.GetSFXMuted())
+			fnCtx.MarkSettingsDirty()
 		}})
 	opts.AddBackOption(&NavOption{ Label: "BACK", To: Back })
 
@@ -152,6 +159,7 @@ func (self *Menu) NewGameOptionsOptionList(ctx *context.Context) {
 		Label: "PIXEL PERFECT",
 		OnConfirm: func(ctx *context.Context) error {
 			ctx.Settings.ScreenFit = settings.ScreenFitPixelPerfect
+			ctx.SaveSettings()
 			return nil
 		},
 		HighlightFunc: func(ctx *context.Context) bool {
@@ -162,6 +170,7 @@ func (self *Menu) NewGameOptionsOptionList(ctx *context.Context) {
 		Label: "PROPORTIONAL",
 		OnConfirm: func(ctx *context.Context) error {
 			ctx.Settings.ScreenFit = settings.ScreenFitProportional
+			ctx.SaveSettings()
 			return nil
 		},
 		HighlightFunc: func(ctx *context.Context) bool {
@@ -172,6 +181,7 @@ func (self *Menu) NewGameOptionsOptionList(ctx *context.Context) {
 		Label: "STRETCHED",
 		OnConfirm: func(ctx *context.Context) error {
 			ctx.Settings.ScreenFit = settings.ScreenFitStretch
+			ctx.SaveSettings()
 			return nil
 		},
 		HighlightFunc: func(ctx *context.Context) bool {
@@ -179,4 +189,19 @@ func (self *Menu) NewGameOptionsOptionList(ctx *context.Context) {
 		},
 	})
 	opts.AddBackOption(&NavOption{ Label: "BACK", To: Back })
+
+	opts = self.NewOptionList(OptsGamepad)
+	opts.Add(&PercentOption{
+		BaseLabel: "STICK DEADZONE",
+		GetPercent: func() uint8 {
+			return ctx.Settings.StickDeadzone
+		},
+		SetPercent: func(fnCtx *context.Context, percent uint8) {
+			fnCtx.Settings.StickDeadzone = percent
+			fnCtx.Input.Gamepad().Config().SetAxisDeadzone(float64(percent)/100.0)
+			fnCtx.MarkSettingsDirty()
+		},
+		MaxPercent: settings.MaxStickDeadzone,
+	})
+	opts.AddBackOption(&NavOption{ Label: "BACK", To: Back })
 }
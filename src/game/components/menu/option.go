@@ -58,6 +58,25 @@ func (self *SceneChangeEffectOption) Confirm(ctx *context.Context) (Key, *scene.
 	return NoChange, &self.Change, self.OnConfirm(ctx)
 }
 
+// Like SceneChangeEffectOption, but the scene change is refused
+// if OnConfirm returns false.
+type SceneChangeCheckOption struct {
+	Label string
+	Change scene.Change
+	OnConfirm func(*context.Context) bool
+}
+func (self *SceneChangeCheckOption) Name() string { return self.Label }
+func (self *SceneChangeCheckOption) HoverUpdate(ctx *context.Context) {}
+func (self *SceneChangeCheckOption) SoftHighlight(ctx *context.Context) bool { return false }
+func (self *SceneChangeCheckOption) Confirm(ctx *context.Context) (Key, *scene.Change, error) {
+	if !self.OnConfirm(ctx) {
+		ctx.Audio.PlaySFX(au.SfxScratch)
+		return NoConfirm, nil, nil
+	}
+	ctx.Input.Unwind()
+	return NoChange, &self.Change, nil
+}
+
 type BasicOption struct {
 	Label string
 	Func func(*context.Context) Key
@@ -96,11 +115,12 @@ func (self *EffectOptionWithHighlight) Confirm(ctx *context.Context) (Key, *scen
 
 type PercentOption struct {
 	BaseLabel string
-	NotifyPercent func(uint8)
-	Percent uint8
+	GetPercent func() uint8
+	SetPercent func(ctx *context.Context, percent uint8)
+	MaxPercent uint8 // 100 if zero
 }
 func (self *PercentOption) Name() string {
-	perc := strconv.Itoa(int(self.Percent))
+	perc := strconv.Itoa(int(self.GetPercent()))
 	return self.BaseLabel + " " + string(text.TriangleLeftWithPad) + perc + string(text.TriangleRightWithPad)
 }
 func (self *PercentOption) MaxName() string {
@@ -108,14 +128,17 @@ func (self *PercentOption) MaxName() string {
 }
 func (self *PercentOption) SoftHighlight(ctx *context.Context) bool { return false }
 func (self *PercentOption) HoverUpdate(ctx *context.Context) {
+	maxPercent := self.MaxPercent
+	if maxPercent == 0 { maxPercent = 100 }
+	percent := self.GetPercent()
 	dir := ctx.Input.RepeatDirAs(in.RFDefault, in.RNDefault)
 	if dir == in.DirRight {
-		if self.Percent < 100 { self.Percent += 1 }
-		self.NotifyPercent(self.Percent)
+		if percent < maxPercent { percent += 1 }
+		self.SetPercent(ctx, percent)
 		ctx.Audio.PlaySFX(au.SfxClick)
 	} else if dir == in.DirLeft {
-		if self.Percent > 0 { self.Percent -= 1 }
-		self.NotifyPercent(self.Percent)
+		if percent > 0 { percent -= 1 }
+		self.SetPercent(ctx, percent)
 		ctx.Audio.PlaySFX(au.SfxClick)
 	}
 }
@@ -11,6 +11,11 @@ import "github.com/tinne26/luckyfeet/src/game/context"
 import "github.com/tinne26/luckyfeet/src/game/utils"
 
 func Draw(canvas *ebiten.Image, ctx *context.Context, ticks int) {
+	DrawWithLabel(canvas, ctx, ticks, "")
+}
+
+// Like Draw, with an extra label after the time (e.g. "REPLAY").
+func DrawWithLabel(canvas *ebiten.Image, ctx *context.Context, ticks int, label string) {
 	white := color.RGBA{244, 244, 244, 244} // slightly translucid
 	black := color.RGBA{ 16,  16,  16, 255}
 	scale  := 2
@@ -20,6 +25,7 @@ func Draw(canvas *ebiten.Image, ctx *context.Context, ticks int) {
 	horzBoxOffset := 8
 
 	var txt []string = []string{ utils.FmtTicksToTimeStrSecs(ticks) }
+	if label != "" { txt[0] += " " + label }
 	txtWidth := text.MeasureLineWidth(txt[0], scale)
 	rect := image.Rect(pad, pad, pad + txtWidth + horzBoxOffset*2, pad + text.LineHeight*scale + vertBoxOffset*2)
 	text.DrawRectBox(canvas, rect, 1, white, black, scale)
@@ -0,0 +1,127 @@
+package racetimer
+
+import "image"
+import "strconv"
+import "image/color"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+import "github.com/tinne26/luckyfeet/src/lib/text"
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/utils"
+
+var goldColor = color.RGBA{214, 150,   0, 255}
+var redColor  = color.RGBA{214,  36,  36, 255}
+var currColor = color.RGBA{255,  71,  20, 255}
+
+const maxListRows = 8
+
+// Split timing data for the current run. All values are ticks
+// elapsed since the race started, except for golds.
+type Splits struct {
+	Times []int // end of each completed segment (one per map visit)
+	Comparison []int // end of each segment on the compared run, may be nil
+	Golds []int // best duration for each segment, may be nil
+}
+
+func (self *Splits) segmentDuration(index int) int {
+	if index == 0 { return self.Times[0] }
+	return self.Times[index] - self.Times[index - 1]
+}
+
+// Returns the delta and color for a completed segment, or false
+// if there's nothing to compare against.
+func (self *Splits) splitDelta(index int) (int, color.RGBA, bool) {
+	if index >= len(self.Comparison) { return 0, color.RGBA{}, false }
+	delta := self.Times[index] - self.Comparison[index]
+	if index < len(self.Golds) && self.segmentDuration(index) < self.Golds[index] {
+		return delta, goldColor, true
+	}
+	if delta > 0 { return delta, redColor, true }
+	return delta, text.BackColor, true
+}
+
+// Returns the running delta for the current segment, which is only
+// shown once the comparison split has been exceeded.
+func (self *Splits) liveDelta(ticks int) (int, color.RGBA, bool) {
+	index := len(self.Times)
+	if index < len(self.Comparison) && ticks > self.Comparison[index] {
+		return ticks - self.Comparison[index], redColor, true
+	}
+	if index == 0 { return 0, color.RGBA{}, false }
+	return self.splitDelta(index - 1)
+}
+
+// Draws the race timer with centiseconds, the current segment and
+// the running delta. If expanded, the full split list is drawn too.
+func DrawSplits(canvas *ebiten.Image, ctx *context.Context, ticks int, label string, splits *Splits, expanded bool) {
+	white := color.RGBA{244, 244, 244, 244} // slightly translucid
+	black := text.BackColor
+	scale  := 2
+
+	pad := 4
+	vertBoxOffset := 5
+	horzBoxOffset := 8
+	lineHeight := text.LineHeight*scale
+	rowHeight := lineHeight + text.LineInterspace*scale
+
+	// main timer box
+	timeStr := utils.FmtTicksToTimeStrCents(ticks)
+	if label != "" { timeStr += " " + label }
+	segStr := "SEG " + strconv.Itoa(len(splits.Times) + 1)
+	delta, deltaClr, hasDelta := splits.liveDelta(ticks)
+	var deltaStr string
+	if hasDelta { deltaStr = utils.FmtTicksToDeltaStrCents(delta) }
+	segWidth := text.MeasureLineWidth(segStr + " ", scale)
+	width := max(text.MeasureLineWidth(timeStr, scale), segWidth + text.MeasureLineWidth(deltaStr, scale))
+	rect := image.Rect(pad, pad, pad + width + horzBoxOffset*2, pad + lineHeight + rowHeight + vertBoxOffset*2)
+	text.DrawRectBox(canvas, rect, 1, white, black, scale)
+	x, y := pad + horzBoxOffset, pad + vertBoxOffset
+	text.DrawLine(canvas, timeStr, x, y, black, scale)
+	text.DrawLine(canvas, segStr, x, y + rowHeight, black, scale)
+	if hasDelta { text.DrawLine(canvas, deltaStr, x + segWidth, y + rowHeight, deltaClr, scale) }
+	if !expanded { return }
+
+	// split list box, one row per segment
+	current := len(splits.Times)
+	rows := max(len(splits.Comparison), current + 1)
+	first := max(0, min(current - maxListRows/2, rows - maxListRows))
+	last  := min(rows, first + maxListRows)
+	labelWidth := text.MeasureLineWidth("SEG " + strconv.Itoa(last) + " ", scale)
+	timeWidth  := text.MeasureLineWidth("00:00.00 ", scale)
+	deltaWidth := text.MeasureLineWidth("+00:00.00", scale)
+	listWidth  := labelWidth + timeWidth + deltaWidth
+	listTop := rect.Max.Y + pad
+	rect = image.Rect(pad, listTop, pad + listWidth + horzBoxOffset*2, listTop + (last - first)*rowHeight - text.LineInterspace*scale + vertBoxOffset*2)
+	text.DrawRectBox(canvas, rect, 1, white, black, scale)
+	y = listTop + vertBoxOffset
+	for i := first; i < last; i++ {
+		labelClr := black
+		if i == current { labelClr = currColor }
+		text.DrawLine(canvas, "SEG " + strconv.Itoa(i + 1), x, y, labelClr, scale)
+
+		var rowTime int = -1
+		var hasRowDelta bool
+		switch {
+		case i < current:
+			rowTime = splits.Times[i]
+			delta, deltaClr, hasRowDelta = splits.splitDelta(i)
+		case i == current:
+			rowTime = ticks
+			if i < len(splits.Comparison) && ticks > splits.Comparison[i] {
+				delta, deltaClr, hasRowDelta = ticks - splits.Comparison[i], redColor, true
+			}
+		case i < len(splits.Comparison):
+			rowTime = splits.Comparison[i]
+		}
+		if rowTime >= 0 {
+			text.DrawLine(canvas, utils.FmtTicksToTimeStrCents(rowTime), x + labelWidth, y, black, scale)
+		}
+		if hasRowDelta {
+			deltaStr = utils.FmtTicksToDeltaStrCents(delta)
+			text.DrawLine(canvas, deltaStr, x + labelWidth + timeWidth, y, deltaClr, scale)
+		}
+		y += rowHeight
+	}
+}
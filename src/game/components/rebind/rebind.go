@@ -0,0 +1,391 @@
+package rebind
+
+import "fmt"
+import "slices"
+import "strings"
+import "image/color"
+
+import "github.com/hajimehoshi/ebiten/v2"
+import "github.com/hajimehoshi/ebiten/v2/inpututil"
+
+import "github.com/tinne26/luckyfeet/src/lib/input"
+import "github.com/tinne26/luckyfeet/src/lib/text"
+
+import "github.com/tinne26/luckyfeet/src/game/material/in"
+import "github.com/tinne26/luckyfeet/src/game/material/au"
+import "github.com/tinne26/luckyfeet/src/game/components/menu"
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/utils"
+
+const (
+	captureTimeout = 120*6 // ticks without any input before giving up
+	cancelHoldTicks = 120 // ticks holding back alone to cancel a capture
+	visibleRows = 9
+	rowsStartY = 56
+	rowHeight = 24
+)
+
+type captureMode uint8
+const (
+	captureNone captureMode = iota
+	captureReplace // new trigger replaces the current one
+	captureAdd // new single key or button becomes an alternative
+)
+
+// Full screen layer to rebind the in.Bindings actions. Bindings
+// are captured by pressing the new key or button (or several at
+// once, for combos), and saved right away.
+type Layer struct {
+	visible bool
+	index int // binding index, or len(in.Bindings) + 0/1 for reset/back
+	scroll int
+
+	capture captureMode
+	captureTicks int
+	backHoldTicks int
+	waitingRelease bool
+	keys []ebiten.Key
+	buttons []input.GamepadStandardInput
+	pressedKeys []ebiten.Key // scratch
+	pressedButtons []input.GamepadStandardInput // scratch
+
+	message string
+	warning bool
+}
+
+func New() *Layer {
+	return &Layer{}
+}
+
+func (self *Layer) IsVisible() bool {
+	return self.visible
+}
+
+func (self *Layer) Show() {
+	self.visible = true
+	self.index = 0
+	self.scroll = 0
+	self.capture = captureNone
+	self.message = ""
+}
+
+func (self *Layer) NewOption(label string) menu.Option {
+	return &menu.EffectOption{
+		Label: label,
+		OnConfirm: func(*context.Context) error {
+			self.Show()
+			return nil
+		},
+	}
+}
+
+func (self *Layer) resetIndex() int { return len(in.Bindings) }
+func (self *Layer) backIndex()  int { return len(in.Bindings) + 1 }
+func (self *Layer) numRows()    int { return len(in.Bindings) + 2 }
+
+func (self *Layer) Update(ctx *context.Context) {
+	if !self.visible { return }
+	if self.capture != captureNone {
+		self.updateCapture(ctx)
+		return
+	}
+
+	if ctx.Input.Trigger(in.ActionBack) {
+		ctx.Audio.PlaySFX(au.SfxBack)
+		self.visible = false
+		return
+	}
+
+	if ctx.Input.Trigger(in.ActionConfirm) {
+		switch self.index {
+		case self.backIndex():
+			ctx.Audio.PlaySFX(au.SfxBack)
+			self.visible = false
+		case self.resetIndex():
+			ctx.Audio.PlaySFX(au.SfxConfirm)
+			in.ResetBindings(ctx.Input)
+			saveBindings(ctx)
+			self.setMessage("DEFAULT CONTROLS RESTORED", false)
+		default:
+			ctx.Audio.PlaySFX(au.SfxConfirm)
+			self.startCapture(captureReplace)
+		}
+		return
+	}
+
+	dir := ctx.Input.RepeatDirAs(in.RFDefault, in.RNSlow)
+	switch dir {
+	case in.DirUp:
+		self.index -= 1
+		if self.index < 0 { self.index = self.numRows() - 1 }
+		ctx.Audio.PlaySFX(au.SfxClick)
+	case in.DirDown:
+		self.index += 1
+		if self.index >= self.numRows() { self.index = 0 }
+		ctx.Audio.PlaySFX(au.SfxClick)
+	case in.DirRight:
+		if self.index >= len(in.Bindings) { return }
+		ctx.Audio.PlaySFX(au.SfxConfirm)
+		self.startCapture(captureAdd)
+	case in.DirLeft:
+		if self.index >= len(in.Bindings) { return }
+		self.clearBinding(ctx, &in.Bindings[self.index])
+	}
+
+	// keep selection within the visible rows
+	if self.index < self.scroll { self.scroll = self.index }
+	if self.index >= self.scroll + visibleRows { self.scroll = self.index - visibleRows + 1 }
+}
+
+func (self *Layer) startCapture(mode captureMode) {
+	self.capture = mode
+	self.captureTicks = 0
+	self.backHoldTicks = 0
+	self.waitingRelease = true // the key used to start the capture
+	self.keys = self.keys[ : 0]
+	self.buttons = self.buttons[ : 0]
+	self.message = ""
+}
+
+// Captures happen in two steps: first we wait for everything to
+// be released, then we accumulate all keys or buttons pressed
+// until they are all released again. Only one device is captured.
+// Holding a back key or button alone cancels the capture, while
+// tapping it binds it like any other.
+func (self *Layer) updateCapture(ctx *context.Context) {
+	self.pressedKeys = inpututil.AppendPressedKeys(self.pressedKeys[ : 0])
+	self.pressedButtons = ctx.Input.Gamepad().AppendPressedInputs(self.pressedButtons[ : 0])
+	anyPressed := len(self.pressedKeys) > 0 || len(self.pressedButtons) > 0
+
+	if self.waitingRelease {
+		if !anyPressed { self.waitingRelease = false }
+		return
+	}
+
+	if len(self.keys) == 0 && len(self.buttons) == 0 {
+		if !anyPressed {
+			self.captureTicks += 1
+			if self.captureTicks >= captureTimeout {
+				ctx.Audio.PlaySFX(au.SfxBack)
+				self.capture = captureNone
+				ctx.Input.Unwind()
+			}
+			return
+		}
+		if len(self.pressedKeys) > 0 { // keyboard wins ties
+			self.keys = append(self.keys, self.pressedKeys...)
+		} else {
+			self.buttons = append(self.buttons, self.pressedButtons...)
+		}
+		return
+	}
+
+	if len(self.keys) > 0 {
+		if len(self.pressedKeys) > 0 {
+			for _, key := range self.pressedKeys {
+				if !slices.Contains(self.keys, key) { self.keys = append(self.keys, key) }
+			}
+			self.updateBackHold(ctx)
+			return
+		}
+	} else if len(self.pressedButtons) > 0 {
+		for _, button := range self.pressedButtons {
+			if !slices.Contains(self.buttons, button) { self.buttons = append(self.buttons, button) }
+		}
+		self.updateBackHold(ctx)
+		return
+	}
+
+	// everything released, apply the new binding
+	binding := &in.Bindings[self.index]
+	mode := self.capture
+	self.capture = captureNone
+	ctx.Input.Unwind()
+	var applied bool
+	if len(self.keys) > 0 {
+		applied = self.applyKeys(binding, mode, ctx.Input.Keyboard().Config())
+	} else {
+		applied = self.applyButtons(binding, mode, ctx.Input.Gamepad().Config())
+	}
+	if !applied {
+		ctx.Audio.PlaySFX(au.SfxScratch)
+		self.setMessage("ONLY SINGLE KEYS OR BUTTONS CAN BE ADDED", true)
+		return
+	}
+	ctx.Audio.PlaySFX(au.SfxConfirm)
+	saveBindings(ctx)
+
+	kbConflicts, gpConflicts := in.Conflicts(ctx.Input, binding.Action)
+	conflicts := gpConflicts
+	if len(self.keys) > 0 { conflicts = kbConflicts }
+	if len(conflicts) > 0 {
+		self.setMessage("ALSO USED BY: " + strings.Join(conflicts, ", "), true)
+	} else {
+		self.setMessage(binding.Name + " UPDATED", false)
+	}
+}
+
+// Cancels the capture once a lone back key or button has been held
+// long enough. Nothing else can have been pressed in between.
+func (self *Layer) updateBackHold(ctx *context.Context) {
+	var back bool
+	if len(self.keys) > 0 {
+		back = len(self.keys) == 1 && in.KeyTriggers(ctx.Input, in.ActionBack, self.keys[0])
+	} else {
+		back = len(self.buttons) == 1 && in.ButtonTriggers(ctx.Input, in.ActionBack, self.buttons[0])
+	}
+	if !back { return }
+	self.backHoldTicks += 1
+	if self.backHoldTicks < cancelHoldTicks { return }
+	ctx.Audio.PlaySFX(au.SfxBack)
+	self.capture = captureNone
+	ctx.Input.Unwind()
+}
+
+func (self *Layer) applyKeys(binding *in.Binding, mode captureMode, config *input.KeyboardConfig) bool {
+	if mode == captureReplace {
+		if len(self.keys) == 1 {
+			config.MapTriggerAction(binding.Action, input.SingleKey(self.keys[0]))
+		} else {
+			config.MapTriggerAction(binding.Action, input.NewMultiKey(self.keys...))
+		}
+		return true
+	}
+
+	if len(self.keys) > 1 { return false }
+	key := self.keys[0]
+	switch current := config.GetMapping(binding.Action).(type) {
+	case nil, input.UnassignedKey:
+		config.MapTriggerAction(binding.Action, input.SingleKey(key))
+	case input.SingleKey:
+		if ebiten.Key(current) == key { return true }
+		config.MapTriggerAction(binding.Action, input.NewKeyList(ebiten.Key(current), key))
+	case input.KeyList:
+		if slices.Contains(current, key) { return true }
+		config.MapTriggerAction(binding.Action, append(slices.Clone(current), key))
+	default: // combos can't have alternatives
+		return false
+	}
+	return true
+}
+
+func (self *Layer) applyButtons(binding *in.Binding, mode captureMode, config *input.GamepadConfig) bool {
+	if mode == captureReplace {
+		if len(self.buttons) == 1 {
+			config.MapTriggerAction(binding.Action, input.SingleButton(self.buttons[0]))
+		} else {
+			config.MapTriggerAction(binding.Action, input.NewMultiButton(self.buttons...))
+		}
+		return true
+	}
+
+	if len(self.buttons) > 1 { return false }
+	button := self.buttons[0]
+	switch current := config.GetMapping(binding.Action).(type) {
+	case nil, input.UnassignedButton:
+		config.MapTriggerAction(binding.Action, input.SingleButton(button))
+	case input.SingleButton:
+		if input.GamepadStandardInput(current) == button { return true }
+		config.MapTriggerAction(binding.Action, input.NewButtonList(input.GamepadStandardInput(current), button))
+	case input.ButtonList:
+		if slices.Contains(current, button) { return true }
+		config.MapTriggerAction(binding.Action, append(slices.Clone(current), button))
+	default: // combos can't have alternatives
+		return false
+	}
+	return true
+}
+
+// Clears the binding for the device shown on screen.
+func (self *Layer) clearBinding(ctx *context.Context, binding *in.Binding) {
+	if binding.Essential {
+		ctx.Audio.PlaySFX(au.SfxScratch)
+		self.setMessage(binding.Name + " MUST STAY ASSIGNED", true)
+		return
+	}
+
+	ctx.Audio.PlaySFX(au.SfxBack)
+	if ctx.Input.UsedGamepadMoreRecentlyThanKeyboard() {
+		ctx.Input.Gamepad().Config().DeleteTriggerAction(binding.Action)
+	} else {
+		ctx.Input.Keyboard().Config().DeleteTriggerAction(binding.Action)
+	}
+	saveBindings(ctx)
+	self.setMessage(binding.Name + " CLEARED", false)
+}
+
+func (self *Layer) setMessage(message string, warning bool) {
+	self.message = message
+	self.warning = warning
+}
+
+func saveBindings(ctx *context.Context) {
+	err := in.SaveBindings(ctx.Input)
+	if err != nil { fmt.Printf("[Bindings not saved: %s]\n", err) }
+}
+
+func (self *Layer) Draw(canvas *ebiten.Image, ctx *context.Context) {
+	white  := color.RGBA{244, 244, 244, 244}
+	orange := color.RGBA{255, 71, 20, 255}
+	gold   := color.RGBA{255, 200, 60, 255}
+	utils.FillOver(canvas, color.RGBA{0, 0, 0, 180})
+
+	usingGamepad := ctx.Input.UsedGamepadMoreRecentlyThanKeyboard()
+	title := "REBIND CONTROLS (KEYBOARD)"
+	if usingGamepad { title = "REBIND CONTROLS (GAMEPAD)" }
+	bounds := canvas.Bounds()
+	x := bounds.Dx()/2
+	text.CenterDrawAt(canvas, x, 28, []string{ title }, white, 2)
+
+	nameX, triggerX := 48, bounds.Dx() - 48
+	y := rowsStartY
+	for i := self.scroll; i < min(self.scroll + visibleRows, self.numRows()); i++ {
+		clr := white
+		if i == self.index {
+			rect := utils.Rect(nameX - 12, y - 5, triggerX + 12, y + text.LineHeight*2 + 5)
+			text.DrawRectBox(canvas, rect, text.BoxBorderOffset, white, orange, 2)
+			clr = orange
+		}
+
+		switch i {
+		case self.resetIndex():
+			text.DrawLine(canvas, "RESET TO DEFAULTS", nameX, y, clr, 2)
+		case self.backIndex():
+			text.DrawLine(canvas, "BACK", nameX, y, clr, 2)
+		default:
+			binding := &in.Bindings[i]
+			text.DrawLine(canvas, binding.Name, nameX, y, clr, 2)
+			var triggerName string
+			if i == self.index && self.capture != captureNone {
+				triggerName = "PRESS A KEY OR BUTTON..."
+			} else if usingGamepad {
+				triggerName = in.GamepadTriggerName(ctx.Input.Gamepad().Config().GetMapping(binding.Action))
+			} else {
+				triggerName = in.KeyboardTriggerName(ctx.Input.Keyboard().Config().GetMapping(binding.Action))
+			}
+			if text.MeasureLineWidth(triggerName, 2) > triggerX - nameX - 200 {
+				text.RightDrawAt(canvas, triggerX, y + text.LineHeight/2, []string{ triggerName }, clr, 1)
+			} else {
+				text.RightDrawAt(canvas, triggerX, y, []string{ triggerName }, clr, 2)
+			}
+		}
+		y += rowHeight
+	}
+
+	// message and hints
+	y = rowsStartY + visibleRows*rowHeight + 14
+	if self.message != "" {
+		clr := gold
+		if self.warning { clr = orange }
+		text.CenterDrawAt(canvas, x, y, []string{ self.message }, clr, 2)
+	}
+	hint := "CONFIRM: REBIND  RIGHT: ADD  LEFT: CLEAR"
+	if self.capture != captureNone {
+		backName := in.KeyboardTriggerName(ctx.Input.Keyboard().Config().GetMapping(in.ActionBack))
+		if usingGamepad {
+			backName = in.GamepadTriggerName(ctx.Input.Gamepad().Config().GetMapping(in.ActionBack))
+		}
+		hint = "HOLD " + backName + ": CANCEL"
+	}
+	text.CenterDrawAt(canvas, x, y + 26, []string{ hint }, white, 2)
+}
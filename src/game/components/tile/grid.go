@@ -0,0 +1,43 @@
+package tile
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+
+const GridCols = 32
+const GridRows = 18
+
+// Per layer occupancy and geometry data, so collision and landing
+// queries can index cells directly instead of binary searching the
+// sorted tile slices several times per pixel step. Tiles outside
+// the grid are kept in the layer, but they are never collidable.
+type layerGrid struct {
+	tiles [GridRows][GridCols]Tile // ID == 0 for empty cells
+	geometries [GridRows][GridCols]uint8 // 0 for empty cells
+}
+
+func (self *layerGrid) rebuild(tiles []Tile) {
+	*self = layerGrid{}
+	for i, _ := range tiles {
+		self.set(tiles[i])
+	}
+}
+
+func (self *layerGrid) set(tile Tile) {
+	if int(tile.Row) >= GridRows || int(tile.Column) >= GridCols { return }
+	self.tiles[tile.Row][tile.Column] = tile
+	self.geometries[tile.Row][tile.Column] = tcsts.GeometryTable[tile.ID]
+}
+
+func (self *layerGrid) clear(row, col int) {
+	if row < 0 || col < 0 || row >= GridRows || col >= GridCols { return }
+	self.tiles[row][col] = Tile{}
+	self.geometries[row][col] = 0
+}
+
+// Returns the grid cell range covering the given pixel bounds, using
+// the same cell rounding as the original sorted tile searches. If
+// the bounds don't touch the grid, minRow > maxRow or minCol > maxCol.
+func gridRange(ox, oy, fx, fy int) (minRow, minCol, maxRow, maxCol int) {
+	minRow, minCol = max(oy/20, 0), max(ox/20, 0)
+	maxRow, maxCol = min(max(fy/20, 0), GridRows - 1), min(max(fx/20, 0), GridCols - 1)
+	return minRow, minCol, maxRow, maxCol
+}
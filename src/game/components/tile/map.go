@@ -4,16 +4,14 @@ import "slices"
 import "errors"
 import "image"
 
-import "github.com/hajimehoshi/ebiten/v2"
-
-import "github.com/tinne26/luckyfeet/src/game/context"
 import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
 import "github.com/tinne26/luckyfeet/src/game/utils"
 import "github.com/tinne26/luckyfeet/src/game/carrot"
 
 // Raw map structure. For actual play, we reorganize data
 // a bit, as most content can be predrawn, and collisions
-// can be prepared into a few 32x18 arrays.
+// are prepared into a few 32x18 arrays. Layers must only
+// be modified through SetTile() and DeleteTile().
 type Map struct {
 	Layers [][]Tile // for indexing, see tcsts.Layer* constants
 	
@@ -21,6 +19,9 @@ type Map struct {
 	TransferIDs [3]uint8 // 0 means undefined, not allowed as a map ID
 	StartRow uint8
 	StartCol uint8
+	Props Props
+
+	grids [tcsts.LayerCountSentinel]layerGrid
 }
 
 func NewMap(id uint8) *Map {
@@ -39,6 +40,7 @@ func (self *Map) SetTile(newTile Tile, layerIndex int) {
 	} else { // insert
 		self.Layers[layerIndex] = slices.Insert(layer, insertPos, newTile)
 	}
+	self.grids[layerIndex].set(newTile)
 }
 
 func (self *Map) DeleteTile(row, col int, layerIndex int) {
@@ -49,103 +51,54 @@ func (self *Map) DeleteTile(row, col int, layerIndex int) {
 	})
 	if !found { return }
 	self.Layers[layerIndex] = slices.Delete(layer, index, index + 1)
+	self.grids[layerIndex].clear(row, col)
 }
 
-func (self *Map) DrawBackLogical(canvas *ebiten.Image, ctx *context.Context, carrots *carrot.Inventory) {
-	for _, layer := range self.Layers[tcsts.LayerBack : tcsts.LayerMain] {
-		for i, _ := range layer {
-			layer[i].Draw(canvas, ctx, carrots)
-		}
-	}
-}
-
-func (self *Map) DrawMainLogical(canvas *ebiten.Image, ctx *context.Context, carrots *carrot.Inventory) {
-	for _, layer := range self.Layers[tcsts.LayerMain : tcsts.LayerFront] {
-		for i, _ := range layer {
-			layer[i].Draw(canvas, ctx, carrots)
-		}
-	}
-}
-
-func (self *Map) DrawFrontLogical(canvas *ebiten.Image, ctx *context.Context, carrots *carrot.Inventory) {
-	for _, layer := range self.Layers[tcsts.LayerFront : ] {
-		for i, _ := range layer {
-			layer[i].Draw(canvas, ctx, carrots)
-		}
-	}
-	if ctx.State.Editing {
-		tile := Tile{ ID: tcsts.StartPoint, Column: self.StartCol, Row: self.StartRow }
-		tile.Draw(canvas, ctx, carrots)
-	}
-}
-
-// Called like tilemap.Collides(ctx, rect, tcsts.LayerBack/LayerMain/LayerFront)
-func (self *Map) Collides(ctx *context.Context, carrots *carrot.Inventory, rect image.Rectangle, layer int) bool {
-	_, found := self.GetFirstCollision(ctx, carrots, rect, layer)
+// Called like tilemap.Collides(carrots, rect, tcsts.LayerBack/LayerMain/LayerFront)
+func (self *Map) Collides(carrots *carrot.Inventory, rect image.Rectangle, layer int) bool {
+	_, found := self.GetFirstCollision(carrots, rect, layer)
 	return found
 }
 
-func (self *Map) GetFirstCollision(ctx *context.Context, carrots *carrot.Inventory, rect image.Rectangle, layer int) (Tile, bool) {
-	tiles := self.Layers[layer]
-	if len(tiles) == 0 { return Tile{}, false }
-	
-	var clamp = func(i int) uint8 { return uint8(min(max(i, 0), 255)) }
-	minCol, minRow := clamp(rect.Min.X/20), clamp(rect.Min.Y/20)
-	maxCol, maxRow := clamp(rect.Max.X/20), clamp(rect.Max.Y/20)
-	minTile := Tile{ Row: minRow, Column: minCol }
-	maxTile := Tile{ Row: maxRow, Column: maxCol }
-
-	// get min/max indices
-	minIndex, _ := slices.BinarySearchFunc(tiles, minTile, func(tile, target Tile) int {
-		return tile.Cmp(target)
-	})
-	if minIndex >= len(tiles) { return Tile{}, false }
-	maxIndex, found := slices.BinarySearchFunc(tiles[minIndex : ], maxTile, func(tile, target Tile) int {
-		return tile.Cmp(target)
-	})
-	maxIndex += minIndex
-	if found { maxIndex += 1 }
-
-	for i, _ := range tiles[minIndex : maxIndex] {
-		col := tiles[minIndex + i].Column
-		if col < minCol || col > maxCol { continue }
-		if tiles[minIndex + i].Collides(ctx, carrots, rect) {
-			return tiles[minIndex + i], true
+func (self *Map) GetFirstCollision(carrots *carrot.Inventory, rect image.Rectangle, layer int) (Tile, bool) {
+	grid := &self.grids[layer]
+	minRow, minCol, maxRow, maxCol := gridRange(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Max.Y)
+	for row := minRow; row <= maxRow; row++ {
+		for col := minCol; col <= maxCol; col++ {
+			if grid.geometries[row][col] <= tcsts.GeometryNone { continue }
+			if grid.tiles[row][col].Collides(carrots, rect) {
+				return grid.tiles[row][col], true
+			}
 		}
 	}
 	return Tile{}, false
 }
 
-func (self *Map) HasLandingFor(ctx *context.Context, carrots *carrot.Inventory, ox, fx, y int, layer int) bool {
-	tiles := self.Layers[layer]
-	if len(tiles) == 0 { return false }
-	
-	var clamp = func(i int) uint8 { return uint8(min(max(i, 0), 255)) }
-	row := clamp(y/20)
-	minCol, maxCol := clamp(ox/20), clamp(fx/20)
-	minTile := Tile{ Row: row, Column: minCol }
-	maxTile := Tile{ Row: row, Column: maxCol }
-
-	// get min/max indices
-	minIndex, _ := slices.BinarySearchFunc(tiles, minTile, func(tile, target Tile) int {
-		return tile.Cmp(target)
-	})
-	if minIndex >= len(tiles) { return false }
-	maxIndex, found := slices.BinarySearchFunc(tiles[minIndex : ], maxTile, func(tile, target Tile) int {
-		return tile.Cmp(target)
-	})
-	maxIndex += minIndex
-	if found { maxIndex += 1 }
-
-	for i, _ := range tiles[minIndex : maxIndex] {
-		col := tiles[minIndex + i].Column
-		if col < minCol || col > maxCol { continue }
-		if tiles[minIndex + i].IsLandingFor(ctx, carrots, ox, fx, y) { return true }
+func (self *Map) HasLandingFor(carrots *carrot.Inventory, ox, fx, y int, layer int) bool {
+	grid := &self.grids[layer]
+	row, minCol, maxRow, maxCol := gridRange(ox, y, fx, y)
+	if row > maxRow { return false }
+	for col := minCol; col <= maxCol; col++ {
+		if grid.geometries[row][col] <= tcsts.GeometryNone { continue }
+		if grid.tiles[row][col].IsLandingFor(carrots, ox, fx, y) { return true }
 	}
 	return false
 }
 
+// Reports whether the given logical position falls within a
+// slope tile on the given layer.
+func (self *Map) IsSlopeAt(x, y int, layer int) bool {
+	if x < 0 || y < 0 || x >= GridCols*20 || y >= GridRows*20 { return false }
+	return tcsts.IsSlopeGeometry(self.grids[layer].geometries[y/20][x/20])
+}
+
 func (self *Map) GetTileIDAt(row, col uint8, layer int) (uint8, bool) {
+	if int(row) < GridRows && int(col) < GridCols {
+		id := self.grids[layer].tiles[row][col].ID
+		if id == 0 { return tcsts.TileTypeMax, false }
+		return id, true
+	}
+
 	target := Tile{ Row: row, Column: col }
 	tiles := self.Layers[layer]
 	index, found := slices.BinarySearchFunc(tiles, target, func(tile, target Tile) int {
@@ -174,6 +127,7 @@ func (self *Map) ExportToString() (string, error) {
 			data = self.Layers[i][tileIndex].EncodeToBytes(data)
 		}
 	}
+	data = self.Props.encodeToBytes(data)
 	
 	return utils.GzipAndEncodeAsCh426(data)
 }
@@ -192,8 +146,15 @@ func (self *Map) LoadFromString(data string) error {
 
 	var layerID uint8 = 255
 	bytes = bytes[6 : ]
+	self.Props = Props{}
 	for len(bytes) > 3 {
 		newLayerID := bytes[0]
+		if newLayerID == PropsMarker {
+			err = self.Props.decodeFromBytes(bytes[1 : ])
+			if err != nil { return err }
+			bytes = bytes[len(bytes) : ]
+			break
+		}
 		if int(newLayerID) >= len(self.Layers) {
 			return errors.New("too many layers encoded in the data")
 		}
@@ -223,7 +184,6 @@ func (self *Map) LoadFromString(data string) error {
 
 	if len(bytes) != 0 { return errors.New("truncated data end") }
 
-
 	// verify proper order of tiles
 	for _, layer := range self.Layers {
 		var prevTile Tile
@@ -235,6 +195,10 @@ func (self *Map) LoadFromString(data string) error {
 		}
 	}
 
+	for i, _ := range self.Layers {
+		self.grids[i].rebuild(self.Layers[i])
+	}
+
 	return nil
 }
 
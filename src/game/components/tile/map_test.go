@@ -0,0 +1,269 @@
+package tile
+
+import "math"
+import "slices"
+import "image"
+import "strings"
+import "testing"
+import "math/rand"
+import "encoding/binary"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+import "github.com/tinne26/luckyfeet/src/game/material/level"
+import "github.com/tinne26/luckyfeet/src/game/player/physics"
+import "github.com/tinne26/luckyfeet/src/game/carrot"
+
+// The original sorted slice searches, kept as a reference for
+// testing and benchmarking the collision grids.
+func (self *Map) getFirstCollisionBySearch(carrots *carrot.Inventory, rect image.Rectangle, layer int) (Tile, bool) {
+	tiles := self.Layers[layer]
+	if len(tiles) == 0 { return Tile{}, false }
+	
+	var clamp = func(i int) uint8 { return uint8(min(max(i, 0), 255)) }
+	minCol, minRow := clamp(rect.Min.X/20), clamp(rect.Min.Y/20)
+	maxCol, maxRow := clamp(rect.Max.X/20), clamp(rect.Max.Y/20)
+	minTile := Tile{ Row: minRow, Column: minCol }
+	maxTile := Tile{ Row: maxRow, Column: maxCol }
+
+	minIndex, _ := slices.BinarySearchFunc(tiles, minTile, func(tile, target Tile) int {
+		return tile.Cmp(target)
+	})
+	if minIndex >= len(tiles) { return Tile{}, false }
+	maxIndex, found := slices.BinarySearchFunc(tiles[minIndex : ], maxTile, func(tile, target Tile) int {
+		return tile.Cmp(target)
+	})
+	maxIndex += minIndex
+	if found { maxIndex += 1 }
+
+	for i, _ := range tiles[minIndex : maxIndex] {
+		col := tiles[minIndex + i].Column
+		if col < minCol || col > maxCol { continue }
+		if tiles[minIndex + i].Collides(carrots, rect) {
+			return tiles[minIndex + i], true
+		}
+	}
+	return Tile{}, false
+}
+
+func (self *Map) hasLandingForBySearch(carrots *carrot.Inventory, ox, fx, y int, layer int) bool {
+	tiles := self.Layers[layer]
+	if len(tiles) == 0 { return false }
+	
+	var clamp = func(i int) uint8 { return uint8(min(max(i, 0), 255)) }
+	row := clamp(y/20)
+	minCol, maxCol := clamp(ox/20), clamp(fx/20)
+	minTile := Tile{ Row: row, Column: minCol }
+	maxTile := Tile{ Row: row, Column: maxCol }
+
+	minIndex, _ := slices.BinarySearchFunc(tiles, minTile, func(tile, target Tile) int {
+		return tile.Cmp(target)
+	})
+	if minIndex >= len(tiles) { return false }
+	maxIndex, found := slices.BinarySearchFunc(tiles[minIndex : ], maxTile, func(tile, target Tile) int {
+		return tile.Cmp(target)
+	})
+	maxIndex += minIndex
+	if found { maxIndex += 1 }
+
+	for i, _ := range tiles[minIndex : maxIndex] {
+		col := tiles[minIndex + i].Column
+		if col < minCol || col > maxCol { continue }
+		if tiles[minIndex + i].IsLandingFor(carrots, ox, fx, y) { return true }
+	}
+	return false
+}
+
+func loadTestMaps(t testing.TB) []*Map {
+	var maps []*Map
+	for _, key := range []level.Key{ level.Guidance, level.FirstRace } {
+		for _, str := range strings.Split(strings.TrimSpace(level.GetData(key)), ".") {
+			tilemap, err := LoadMapFromString(str)
+			if err != nil { t.Fatal(err) }
+			maps = append(maps, tilemap)
+		}
+	}
+	return maps
+}
+
+func testInventory() *carrot.Inventory {
+	var carrots carrot.Inventory
+	carrots.Carrots[0] = carrot.Carrot{ Variety: carrot.Orange }
+	carrots.FillLevels[0] = 0.5
+	return &carrots
+}
+
+// Player sized rects and landing zones all over the screen, including
+// slightly out of bounds, as the player can jump above the screen.
+func randomQueries(rng *rand.Rand, n int) ([]image.Rectangle, [][3]int) {
+	rects := make([]image.Rectangle, n)
+	landings := make([][3]int, n)
+	for i := 0; i < n; i++ {
+		x, y := rng.Intn(680) - 20, rng.Intn(420) - 40
+		rects[i] = image.Rect(x, y, x + 9, y + 28)
+		landings[i] = [3]int{x, x + 7, y + 28}
+	}
+	return rects, landings
+}
+
+func TestGridMatchesSearch(t *testing.T) {
+	maps := loadTestMaps(t)
+	carrots := testInventory()
+	rng := rand.New(rand.NewSource(26))
+	rects, landings := randomQueries(rng, 20000)
+
+	// also test edits on a copy of the first map
+	edited, err := LoadMapFromString(strings.Split(level.GetData(level.FirstRace), ".")[0])
+	if err != nil { t.Fatal(err) }
+	for i := 0; i < 200; i++ {
+		row, col := rng.Intn(GridRows), rng.Intn(GridCols)
+		if rng.Intn(3) == 0 {
+			edited.DeleteTile(row, col, tcsts.LayerMain)
+		} else {
+			id := uint8(1 + rng.Intn(tcsts.TileTypeMax - 1))
+			orientation := Orientation(rng.Intn(8))
+			edited.SetTile(Tile{ ID: id, Orientation: orientation, Row: uint8(row), Column: uint8(col) }, tcsts.LayerMain)
+		}
+	}
+	maps = append(maps, edited)
+
+	for mapIndex, tilemap := range maps {
+		for _, layer := range []int{ tcsts.LayerBack, tcsts.LayerMain, tcsts.LayerFront, tcsts.LayerSpecial } {
+			for i, rect := range rects {
+				gridTile, gridFound := tilemap.GetFirstCollision(carrots, rect, layer)
+				searchTile, searchFound := tilemap.getFirstCollisionBySearch(carrots, rect, layer)
+				if gridTile != searchTile || gridFound != searchFound {
+					t.Fatalf("map #%d, layer %d, query #%d: collision for %v expected %v (%t), but got %v (%t)", mapIndex, layer, i, rect, searchTile, searchFound, gridTile, gridFound)
+				}
+
+				ox, fx, y := landings[i][0], landings[i][1], landings[i][2]
+				for dy := 0; dy < 20; dy++ { // random y values would rarely land
+					gridLanding := tilemap.HasLandingFor(carrots, ox, fx, y + dy, layer)
+					searchLanding := tilemap.hasLandingForBySearch(carrots, ox, fx, y + dy, layer)
+					if gridLanding != searchLanding {
+						t.Fatalf("map #%d, layer %d, query #%d: landing for (%d, %d, %d) expected %t, but got %t", mapIndex, layer, i, ox, fx, y + dy, searchLanding, gridLanding)
+					}
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkCollisionsGrid(b *testing.B) {
+	tilemap := loadTestMaps(b)[1]
+	carrots := testInventory()
+	rects, landings := randomQueries(rand.New(rand.NewSource(27)), 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := i & 1023
+		_ = tilemap.Collides(carrots, rects[n], tcsts.LayerMain)
+		_ = tilemap.HasLandingFor(carrots, landings[n][0], landings[n][1], landings[n][2], tcsts.LayerMain)
+	}
+}
+
+func BenchmarkCollisionsSearch(b *testing.B) {
+	tilemap := loadTestMaps(b)[1]
+	carrots := testInventory()
+	rects, landings := randomQueries(rand.New(rand.NewSource(27)), 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := i & 1023
+		_, _ = tilemap.getFirstCollisionBySearch(carrots, rects[n], tcsts.LayerMain)
+		_ = tilemap.hasLandingForBySearch(carrots, landings[n][0], landings[n][1], landings[n][2], tcsts.LayerMain)
+	}
+}
+
+func TestPropsRoundTrip(t *testing.T) {
+	for i, tilemap := range loadTestMaps(t) {
+		if !tilemap.Props.IsZero() {
+			t.Fatalf("test #%d, expected zero props on built-in map", i)
+		}
+
+		tilemap.Props.PhysicsPreset = uint8(i % 3)
+		tilemap.Props.WallJumps = (i % 2 == 0)
+		tilemap.Props.AirDash = (i % 3 == 1)
+		tilemap.Props.Physics = physics.Overrides{}
+		if i % 2 == 1 {
+			tilemap.Props.Physics.Set(physics.FieldRunSpeed, 0.825)
+			tilemap.Props.Physics.Set(physics.FieldDashTicks, float64(i))
+		}
+		str, err := tilemap.ExportToString()
+		if err != nil { t.Fatal(err) }
+		reloaded, err := LoadMapFromString(str)
+		if err != nil { t.Fatalf("test #%d, %s", i, err) }
+		if reloaded.Props != tilemap.Props {
+			t.Fatalf("test #%d, expected props %v, got %v", i, tilemap.Props, reloaded.Props)
+		}
+		for layer, _ := range tilemap.Layers {
+			if !slices.Equal(reloaded.Layers[layer], tilemap.Layers[layer]) {
+				t.Fatalf("test #%d, layer %d mismatch after reload", i, layer)
+			}
+		}
+	}
+
+	// unknown keys are skipped
+	var props Props
+	err := props.decodeFromBytes([]byte{ 200, 2, 9, 9, propKeyPhysicsPreset, 1, 1 })
+	if err != nil { t.Fatal(err) }
+	if props.PhysicsPreset != 1 {
+		t.Fatalf("expected physics preset 1, got %d", props.PhysicsPreset)
+	}
+	err = props.decodeFromBytes([]byte{ propKeyPhysicsPreset, 3, 1 })
+	if err == nil { t.Fatal("expected error on truncated props") }
+	nan := binary.BigEndian.AppendUint64(nil, math.Float64bits(math.NaN()))
+	err = props.decodeFromBytes(append([]byte{ propKeyProfileFirst, 8 }, nan...))
+	if err == nil { t.Fatal("expected error on NaN physics override") }
+}
+
+func TestEntityLayerRoundTrip(t *testing.T) {
+	tilemap := loadTestMaps(t)[0]
+	patroller := Tile{ ID: tcsts.EntityPatroller, Row: 4, Column: 7, Orientation: Orientation(0).Mirrored() }
+	hopper := Tile{ ID: tcsts.EntityHopper, Row: 9, Column: 2 }
+	tilemap.SetTile(patroller, tcsts.LayerEntities)
+	tilemap.SetTile(hopper, tcsts.LayerEntities)
+	str, err := tilemap.ExportToString()
+	if err != nil { t.Fatal(err) }
+	reloaded, err := LoadMapFromString(str)
+	if err != nil { t.Fatal(err) }
+	if !slices.Equal(reloaded.Layers[tcsts.LayerEntities], []Tile{ patroller, hopper }) {
+		t.Fatalf("unexpected entity layer after reload: %v", reloaded.Layers[tcsts.LayerEntities])
+	}
+
+	// entities have no geometry, they must not affect collisions
+	if reloaded.Collides(nil, patroller.RawRect(), tcsts.LayerEntities) {
+		t.Fatal("entity spawn points shouldn't collide")
+	}
+}
+
+func TestSlopeGeometry(t *testing.T) {
+	tilemap := NewMap(1)
+	tilemap.SetTile(Tile{ ID: tcsts.MainSlope45, Row: 2, Column: 3 }, tcsts.LayerMain)
+	ox, oy := 3*20, 2*20
+	footRect := func(footX, minY, maxY int) image.Rectangle { // 9 wide, like the player
+		return image.Rect(ox + footX - 4, oy + minY, ox + footX + 5, oy + maxY)
+	}
+
+	// base orientation rises to the right, surface at 19 - x
+	if !tilemap.Collides(nil, footRect(5, 0, 15), tcsts.LayerMain) { t.Fatal("expected collision with the slope") }
+	if tilemap.Collides(nil, footRect(5, 0, 14), tcsts.LayerMain) { t.Fatal("unexpected collision above the slope") }
+	if tilemap.Collides(nil, footRect(12, 0, 7), tcsts.LayerMain) { t.Fatal("unexpected collision above the slope") }
+	if !tilemap.HasLandingFor(nil, ox + 5, ox + 5, oy + 14, tcsts.LayerMain) { t.Fatal("expected landing") }
+	if tilemap.HasLandingFor(nil, ox + 5, ox + 5, oy + 13, tcsts.LayerMain) { t.Fatal("unexpected landing") }
+	if tilemap.HasLandingFor(nil, ox + 4, ox + 6, oy + 14, tcsts.LayerMain) {
+		t.Fatal("unexpected landing for a multi-column query")
+	}
+	if !tilemap.IsSlopeAt(ox + 1, oy + 1, tcsts.LayerMain) || tilemap.IsSlopeAt(ox - 1, oy + 1, tcsts.LayerMain) {
+		t.Fatal("unexpected IsSlopeAt result")
+	}
+
+	// mirrored rises to the left
+	tilemap.SetTile(Tile{ ID: tcsts.MainSlope45, Orientation: Orientation(0).Mirrored(), Row: 2, Column: 3 }, tcsts.LayerMain)
+	if !tilemap.HasLandingFor(nil, ox + 5, ox + 5, oy + 5, tcsts.LayerMain) { t.Fatal("expected mirrored landing") }
+
+	// upside down slopes can be landed on their flat top side
+	tilemap.SetTile(Tile{ ID: tcsts.MainSlope22High, Orientation: Orientation(0).RotatedRight().RotatedRight(), Row: 2, Column: 3 }, tcsts.LayerMain)
+	for x := 0; x < 20; x++ {
+		if !tilemap.HasLandingFor(nil, ox + x, ox + x, oy, tcsts.LayerMain) {
+			t.Fatalf("expected landing on the flat side at column %d", x)
+		}
+	}
+}
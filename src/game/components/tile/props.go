@@ -0,0 +1,86 @@
+package tile
+
+import "math"
+import "errors"
+import "encoding/binary"
+
+import "github.com/tinne26/luckyfeet/src/game/player/physics"
+
+// Per-map gameplay settings. They are stored in an optional block
+// after the layers, starting with PropsMarker and followed by key,
+// length and value entries. Unknown keys are skipped on load, so
+// new props can be added without breaking existing maps.
+type Props struct {
+	PhysicsPreset uint8 // see physics.Preset* constants
+	Physics physics.Overrides // applied on top of the preset
+	WallJumps bool
+	AirDash bool
+}
+
+const PropsMarker = 0xFF
+
+const (
+	propKeyPhysicsPreset = iota + 1
+	propKeyWallJumps
+	propKeyAirDash
+)
+
+// Physics overrides use one key per physics.ProfileField, starting
+// from this one, with float64 values (8 bytes, big endian).
+const propKeyProfileFirst = 0x10
+
+func (self *Props) IsZero() bool {
+	return *self == Props{}
+}
+
+func (self *Props) encodeToBytes(buffer []byte) []byte {
+	if self.IsZero() { return buffer }
+	buffer = append(buffer, PropsMarker)
+	if self.PhysicsPreset != 0 {
+		buffer = append(buffer, propKeyPhysicsPreset, 1, self.PhysicsPreset)
+	}
+	if self.WallJumps {
+		buffer = append(buffer, propKeyWallJumps, 1, 1)
+	}
+	if self.AirDash {
+		buffer = append(buffer, propKeyAirDash, 1, 1)
+	}
+	for field := physics.ProfileField(0); field < physics.ProfileFieldCountSentinel; field++ {
+		value, found := self.Physics.Get(field)
+		if !found { continue }
+		buffer = append(buffer, propKeyProfileFirst + uint8(field), 8)
+		buffer = binary.BigEndian.AppendUint64(buffer, math.Float64bits(value))
+	}
+	return buffer
+}
+
+// The given bytes must not include the marker.
+func (self *Props) decodeFromBytes(bytes []byte) error {
+	*self = Props{}
+	for len(bytes) > 0 {
+		if len(bytes) < 2 { return errors.New("truncated map props") }
+		key, size := bytes[0], int(bytes[1])
+		if len(bytes) < 2 + size { return errors.New("truncated map prop value") }
+		value := bytes[2 : 2 + size]
+		switch key {
+		case propKeyPhysicsPreset:
+			if size != 1 { return errors.New("invalid physics preset prop") }
+			self.PhysicsPreset = value[0]
+		case propKeyWallJumps:
+			if size != 1 { return errors.New("invalid wall jumps prop") }
+			self.WallJumps = (value[0] != 0)
+		case propKeyAirDash:
+			if size != 1 { return errors.New("invalid air dash prop") }
+			self.AirDash = (value[0] != 0)
+		default:
+			field := physics.ProfileField(key - propKeyProfileFirst)
+			if key < propKeyProfileFirst || field >= physics.ProfileFieldCountSentinel { break }
+			if size != 8 { return errors.New("invalid physics override prop") }
+			override := math.Float64frombits(binary.BigEndian.Uint64(value))
+			if !physics.IsValidOverride(override) { return errors.New("invalid physics override value") }
+			self.Physics.Set(field, override)
+		}
+		bytes = bytes[2 + size : ]
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+//go:build !headless
+
+package tile
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+import "github.com/tinne26/luckyfeet/src/game/carrot"
+
+// Static tiles of a map pre-drawn into one offscreen image per
+// layer. Only dynamic tiles (carrots, carrot platforms, transfers,
+// zones and entity spawn points) are drawn each frame, right after
+// the static tiles of their own layer, so layers keep their order.
+//
+// Switching maps rebuilds the cache automatically, but editing the
+// current map requires calling Invalidate() explicitly.
+type RenderCache struct {
+	tilemap *Map
+	statics [tcsts.LayerCountSentinel]*ebiten.Image
+	hasStatics [tcsts.LayerCountSentinel]bool
+	dynamics [tcsts.LayerCountSentinel][]Tile
+}
+
+func (self *RenderCache) Invalidate() {
+	self.tilemap = nil
+}
+
+func (self *RenderCache) DrawBackLogical(canvas *ebiten.Image, ctx *context.Context, tilemap *Map, carrots *carrot.Inventory) {
+	self.drawLayers(canvas, ctx, tilemap, carrots, tcsts.LayerBack, tcsts.LayerMain)
+}
+
+func (self *RenderCache) DrawMainLogical(canvas *ebiten.Image, ctx *context.Context, tilemap *Map, carrots *carrot.Inventory) {
+	self.drawLayers(canvas, ctx, tilemap, carrots, tcsts.LayerMain, tcsts.LayerFront)
+}
+
+func (self *RenderCache) DrawFrontLogical(canvas *ebiten.Image, ctx *context.Context, tilemap *Map, carrots *carrot.Inventory) {
+	self.drawLayers(canvas, ctx, tilemap, carrots, tcsts.LayerFront, tcsts.LayerCountSentinel)
+	if ctx.State.Editing {
+		tile := Tile{ ID: tcsts.StartPoint, Column: tilemap.StartCol, Row: tilemap.StartRow }
+		tile.Draw(canvas, ctx, carrots)
+	}
+}
+
+func (self *RenderCache) drawLayers(canvas *ebiten.Image, ctx *context.Context, tilemap *Map, carrots *carrot.Inventory, fromLayer, toLayer int) {
+	if self.tilemap != tilemap { self.rebuild(ctx, tilemap) }
+	for layer := fromLayer; layer < toLayer; layer++ {
+		if self.hasStatics[layer] { canvas.DrawImage(self.statics[layer], nil) }
+		for i, _ := range self.dynamics[layer] {
+			self.dynamics[layer][i].Draw(canvas, ctx, carrots)
+		}
+	}
+}
+
+func (self *RenderCache) rebuild(ctx *context.Context, tilemap *Map) {
+	self.tilemap = tilemap
+	for layer, tiles := range tilemap.Layers {
+		self.hasStatics[layer] = false
+		self.dynamics[layer] = self.dynamics[layer][ : 0]
+		if self.statics[layer] != nil { self.statics[layer].Clear() }
+		for i, _ := range tiles {
+			if !tcsts.IsStaticTile(tiles[i].ID) {
+				self.dynamics[layer] = append(self.dynamics[layer], tiles[i])
+				continue
+			}
+			if self.statics[layer] == nil { // empty layers don't need images
+				self.statics[layer] = ebiten.NewImage(640, 360)
+			}
+			self.hasStatics[layer] = true
+			tiles[i].Draw(self.statics[layer], ctx, nil)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package tile
+
+import "image"
+import "math/bits"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+
+// Slopes can't be described with a single rect, so they use
+// per column bitmasks (bit n set means row n is solid), already
+// precomputed for each orientation. Unlike rect geometries,
+// slopes only collide and land through a single "foot" column:
+// the center column of the target rect for collisions, and
+// only single column (ox == fx) queries for landings. Without
+// this, the player collision rect and its landing zone would
+// disagree about the ground height in the middle of a slope.
+type slopeMask [20]uint32
+
+var slopeMasks [3][8]slopeMask // geometry - GeometrySlope45, orientation
+func init() {
+	surfaces := [3]func(x int) int{
+		func(x int) int { return 19 - x }, // GeometrySlope45
+		func(x int) int { return 19 - x/2 }, // GeometrySlope22Low
+		func(x int) int { return  9 - x/2 }, // GeometrySlope22High
+	}
+	for i, surface := range surfaces {
+		for orient := Orientation(0); orient < 8; orient++ {
+			mask := &slopeMasks[i][orient]
+			for x := 0; x < 20; x++ {
+				for y := surface(x); y < 20; y++ {
+					pt := orient.ApplyToTileRect(image.Rect(x, y, x + 1, y + 1)).Min
+					mask[pt.X] |= 1 << pt.Y
+				}
+			}
+		}
+	}
+}
+
+func getSlopeMask(geometry uint8, tileOrient Orientation) *slopeMask {
+	return &slopeMasks[geometry - tcsts.GeometrySlope45][tileOrient]
+}
+
+func slopeCollides(geometry uint8, tileOrient Orientation, tileRect, targetRect image.Rectangle) bool {
+	x := targetRect.Min.X + targetRect.Dx()/2 - tileRect.Min.X
+	if x < 0 || x >= 20 { return false }
+	oy := max(targetRect.Min.Y - tileRect.Min.Y, 0)
+	fy := min(targetRect.Max.Y - tileRect.Min.Y, 20)
+	if oy >= fy { return false }
+	rows := uint32(1 << fy) - uint32(1 << oy)
+	return getSlopeMask(geometry, tileOrient)[x] & rows != 0
+}
+
+func slopeIsLandingFor(geometry uint8, tileOrient Orientation, tileRect image.Rectangle, ox, fx, y int) bool {
+	if ox != fx { return false }
+	x := ox - tileRect.Min.X
+	if x < 0 || x >= 20 { return false }
+	column := getSlopeMask(geometry, tileOrient)[x]
+	if column == 0 { return false }
+	return bits.TrailingZeros32(column) == y - tileRect.Min.Y
+}
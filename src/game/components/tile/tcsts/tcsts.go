@@ -8,6 +8,8 @@ const (
 	LayerFront
 	LayerFrontDecor
 	LayerSpecial
+	LayerZone // wind and updraft areas, not drawn outside the editor
+	LayerEntities // critter spawn points, not drawn outside the editor
 	LayerCountSentinel
 )
 
@@ -86,6 +88,23 @@ const (
 	TransferDownB
 	TransferDownC
 
+	ZoneWindRight
+	ZoneWindLeft
+	ZoneUpdraft
+
+	// slopes are static ground tiles, but they had to be added
+	// at the end to keep the IDs of existing maps valid
+	MainSlope45
+	MainSlope22Low // 22.5 deg. slopes (1:2) take two tiles
+	MainSlope22High
+	FrontSlope45
+	FrontSlope22Low
+	FrontSlope22High
+
+	// entity spawn points, mirroring makes them start facing left
+	EntityPatroller
+	EntityHopper
+
 	TileTypeMax
 	TileNone // out of range, for hacky purposes
 )
@@ -103,10 +122,22 @@ const (
 	GeometryBL17x16 // carrot right plats
 	GeometryBL1_17x16 // carrot single plats
 	GeometryMM4x4 // special target for carrots, goals and transfers
+	GeometrySlope45 // slopes rise from bottom left to top right
+	GeometrySlope22Low
+	GeometrySlope22High
 
 	GeometryMaxSentinel
 )
 
+func IsSlopeGeometry(geometry uint8) bool {
+	return geometry >= GeometrySlope45 && geometry <= GeometrySlope22High
+}
+
+// Static tiles can be predrawn, as they never change while playing.
+func IsStaticTile(id uint8) bool {
+	return id <= RaceGoal || (id >= MainSlope45 && id <= FrontSlope22High)
+}
+
 var GeometryTable [TileTypeMax]uint8
 func init() {
 	// assign no geometry by default
@@ -140,7 +171,7 @@ func init() {
 	GeometryTable[CarrotOrange] = GeometryMM4x4
 	GeometryTable[CarrotYellow] = GeometryMM4x4
 	GeometryTable[CarrotPurple] = GeometryMM4x4
-	for i := TransferUp; i < TileTypeMax; i++ {
+	for i := TransferUp; i <= TransferDownC; i++ {
 		GeometryTable[i] = GeometryMM4x4 // all transfers
 	}
 
@@ -153,4 +184,11 @@ func init() {
 	GeometryTable[MainOrangePlatRight] = GeometryBL17x16
 	GeometryTable[MainYellowPlatRight] = GeometryBL17x16
 	GeometryTable[MainPurplePlatRight] = GeometryBL17x16
+
+	GeometryTable[MainSlope45] = GeometrySlope45
+	GeometryTable[MainSlope22Low] = GeometrySlope22Low
+	GeometryTable[MainSlope22High] = GeometrySlope22High
+	GeometryTable[FrontSlope45] = GeometrySlope45
+	GeometryTable[FrontSlope22Low] = GeometrySlope22Low
+	GeometryTable[FrontSlope22High] = GeometrySlope22High
 }
@@ -0,0 +1,63 @@
+package toast
+
+import "image/color"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+import "github.com/tinne26/luckyfeet/src/lib/text"
+
+const (
+	fadeTicks = 30
+	showTicks = 360 // including fades
+)
+
+// Small notifications shown one after another at the bottom of
+// the screen, over any scene.
+type Queue struct {
+	messages []string
+	elapsed int // ticks the first message has been shown
+}
+
+func (self *Queue) Push(message string) {
+	self.messages = append(self.messages, message)
+}
+
+func (self *Queue) Update() {
+	if len(self.messages) == 0 { return }
+	self.elapsed += 1
+	if self.elapsed >= showTicks {
+		self.messages = self.messages[1 : ]
+		self.elapsed = 0
+	}
+}
+
+func (self *Queue) Draw(canvas *ebiten.Image) {
+	if len(self.messages) == 0 { return }
+
+	alpha := 1.0
+	if self.elapsed < fadeTicks {
+		alpha = float64(self.elapsed)/fadeTicks
+	} else if self.elapsed > showTicks - fadeTicks {
+		alpha = float64(showTicks - self.elapsed)/fadeTicks
+	}
+	white := scaleAlpha(color.RGBA{244, 244, 244, 244}, alpha)
+	black := scaleAlpha(text.BackColor, alpha)
+
+	scale := 2
+	lines := []string{ self.messages[0] }
+	bounds := canvas.Bounds()
+	x := bounds.Dx()/2
+	y := bounds.Dy() - 6 - (text.LineHeight*scale)/2 - text.BoxVertMargin*scale
+	text.DrawCenteredBoxAt(canvas, x, y, lines, white, black, scale)
+	text.CenterDrawAt(canvas, x, y, lines, black, scale)
+}
+
+// colors are premultiplied, so all components are scaled
+func scaleAlpha(clr color.RGBA, alpha float64) color.RGBA {
+	return color.RGBA{
+		uint8(float64(clr.R)*alpha),
+		uint8(float64(clr.G)*alpha),
+		uint8(float64(clr.B)*alpha),
+		uint8(float64(clr.A)*alpha),
+	}
+}
@@ -0,0 +1,63 @@
+package zonefx
+
+import "math/rand"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/components/tile"
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+import "github.com/tinne26/luckyfeet/src/game/components/back"
+
+// Subtle particles drifting along the wind and updraft zones of
+// a map. Zones themselves are invisible outside the editor, so
+// this is the only hint the player gets about them.
+type Particles struct {
+	zones []tile.Tile
+	particles []back.Particle
+	owners []int // zone index for each particle
+	rng *rand.Rand
+	opts ebiten.DrawImageOptions
+}
+
+// Must be called whenever the active map changes. The random
+// source is owned by the caller, so runs can be seeded.
+func (self *Particles) SetZones(tilemap *tile.Map, rng *rand.Rand) {
+	self.rng = rng
+	self.zones = tilemap.Layers[tcsts.LayerZone]
+	self.particles = self.particles[ : 0]
+	self.owners = self.owners[ : 0]
+	for i, _ := range self.zones {
+		self.particles = append(self.particles, back.Particle{})
+		self.owners = append(self.owners, i)
+	}
+}
+
+func (self *Particles) Update() {
+	for i, _ := range self.particles {
+		if self.particles[i].Advance() { continue }
+		self.reroll(&self.particles[i], self.zones[self.owners[i]])
+	}
+}
+
+func (self *Particles) DrawLogical(canvas *ebiten.Image, ctx *context.Context) {
+	for i, _ := range self.particles {
+		self.particles[i].DrawLogical(canvas, ctx, &self.opts)
+	}
+}
+
+func (self *Particles) reroll(particle *back.Particle, zone tile.Tile) {
+	particle.X = float64(zone.Column)*20 + self.rng.Float64()*20
+	particle.Y = float64(zone.Row)*20 + self.rng.Float64()*20
+	particle.Speed = 0.12 + self.rng.Float64()*0.12
+	switch zone.ID {
+	case tcsts.ZoneWindRight: particle.Dir = 0 + uint8(self.rng.Intn(2))*2 // NE or SE
+	case tcsts.ZoneWindLeft : particle.Dir = 1 + uint8(self.rng.Intn(2))*2 // NW or SW
+	case tcsts.ZoneUpdraft  : particle.Dir = uint8(self.rng.Intn(2)) // NE or NW
+	default:
+		panic("broken code")
+	}
+	particle.LifeTicksLeft = 20 + uint16(self.rng.Intn(60))
+	particle.TransitionTicks = 20 + uint16(self.rng.Intn(30))
+	particle.TransitionElapsed = 0
+}
@@ -1,7 +1,11 @@
 package context
 
+import "fmt"
+import "math"
 import "io/fs"
 
+import "github.com/hajimehoshi/ebiten/v2"
+
 import "github.com/tinne26/luckyfeet/src/lib/input"
 import "github.com/tinne26/luckyfeet/src/lib/audio"
 import "github.com/tinne26/luckyfeet/src/lib/scene"
@@ -10,8 +14,14 @@ import "github.com/tinne26/luckyfeet/src/game/graphics/gfxcore"
 import "github.com/tinne26/luckyfeet/src/game/settings"
 import "github.com/tinne26/luckyfeet/src/game/material/in"
 import "github.com/tinne26/luckyfeet/src/game/material/au"
-import "github.com/tinne26/luckyfeet/src/game/material/animations"
+import "github.com/tinne26/luckyfeet/src/game/material/characters"
 import "github.com/tinne26/luckyfeet/src/game/interfaces"
+import "github.com/tinne26/luckyfeet/src/game/storage"
+import "github.com/tinne26/luckyfeet/src/game/records"
+import "github.com/tinne26/luckyfeet/src/game/ghost"
+import "github.com/tinne26/luckyfeet/src/game/stats"
+import "github.com/tinne26/luckyfeet/src/game/achievements"
+import "github.com/tinne26/luckyfeet/src/game/components/toast"
 
 type Context struct {
 	Input *input.KBGP
@@ -20,37 +30,84 @@ type Context struct {
 	State *state.State[*Context]
 	Gfxcore *gfxcore.Graphics
 	Settings *settings.Settings
+	Toasts *toast.Queue // drawn over all scenes
 
 	// --- extra random half hardcoded stuff ---
 	Background interfaces.Background[*Context] // initialized on Start scene
-	Animations *animations.Animations
+	Characters []*characters.Character // see State.CharacterIndex
+
+	settingsDirty bool
 }
 
 func New(filesys fs.FS, sceneManager *scene.Manager[*Context]) (*Context, error) {
 	var err error
 
+	// load settings, falling back to defaults on errors
+	prefs, err := settings.Load()
+	if err != nil {
+		fmt.Printf("[Settings not loaded: %s]\n", err)
+		prefs = settings.New()
+	}
+	if prefs.AllowWinResize {
+		ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	}
+
 	// set up audio context
 	soundscape := audio.NewSoundscape()
 	err = au.LoadAndConfigure(soundscape, filesys)
 	if err != nil { return nil, err }
+	soundscape.SetUserBGMVolume(float32(prefs.MusicLevel)/100.0)
+	soundscape.SetUserSFXVolume(float32(prefs.SfxLevel)/100.0)
+	soundscape.SetBGMMuted(prefs.MusicMuted)
+	soundscape.SetSFXMuted(prefs.SfxMuted)
 	
 	// set up input context
 	kbgp := input.NewKBGP()
 	err = in.LoadAndConfigure(kbgp)
 	if err != nil { return nil, err }
+	err = in.LoadBindings(kbgp)
+	if err != nil { fmt.Printf("[Bindings not loaded: %s]\n", err) }
+	err = in.LoadAndConfigureGamepad(kbgp.Gamepad(), filesys)
+	if err != nil { fmt.Printf("[Gamepad layouts not loaded: %s]\n", err) }
+	kbgp.Gamepad().Config().SetAxisDeadzone(float64(prefs.StickDeadzone)/100.0)
 
 	// create new game state
 	gameState := state.New[*Context]()
-
-	// create new settings
-	prefs := settings.New()
+	bests, err := records.Load()
+	if err != nil {
+		fmt.Printf("[Personal bests not loaded: %s]\n", err)
+		setAside(records.FileName)
+	} else {
+		gameState.Records = bests
+	}
+	lifetime, err := stats.Load()
+	if err != nil {
+		fmt.Printf("[Stats not loaded: %s]\n", err)
+		setAside(stats.FileName)
+	} else {
+		gameState.Stats = lifetime
+	}
+	ghosts, err := ghost.Load()
+	if err != nil {
+		fmt.Printf("[Ghosts not loaded: %s]\n", err)
+		setAside(ghost.FileName)
+	} else {
+		gameState.Ghosts = ghosts
+	}
+	unlocked, err := achievements.Load()
+	if err != nil {
+		fmt.Printf("[Achievements not loaded: %s]\n", err)
+		setAside(achievements.FileName)
+	} else {
+		gameState.Achievements = unlocked
+	}
 
 	// load graphics
 	graphics, err := gfxcore.New(filesys)
 	if err != nil { return nil, err }
 
-	// load animations
-	anims, err := animations.New(filesys)
+	// load playable characters
+	chars, err := characters.Load(filesys)
 	if err != nil { return nil, err }
 	
 	// return new context
@@ -61,10 +118,33 @@ func New(filesys fs.FS, sceneManager *scene.Manager[*Context]) (*Context, error)
 		Scenes: sceneManager,
 		Gfxcore: graphics,
 		Settings: prefs,
-		Animations: anims,
+		Toasts: &toast.Queue{},
+		Characters: chars,
 	}, nil
 }
 
+// Copies the audio levels to the settings and saves them. Must
+// be called after any settings change.
+func (self *Context) SaveSettings() {
+	self.Settings.MusicLevel = uint8(math.Round(float64(self.Audio.GetUserBGMVolume())*100.0))
+	self.Settings.SfxLevel = uint8(math.Round(float64(self.Audio.GetUserSFXVolume())*100.0))
+	self.Settings.MusicMuted = self.Audio.GetBGMMuted()
+	self.Settings.SfxMuted = self.Audio.GetSFXMuted()
+	err := self.Settings.Save()
+	if err != nil { fmt.Printf("[Settings not saved: %s]\n", err) }
+	self.settingsDirty = false
+}
+
+// Marks the settings as changed without saving them yet, for
+// changes that come in quick succession, like volume steps.
+func (self *Context) MarkSettingsDirty() {
+	self.settingsDirty = true
+}
+
+func (self *Context) SaveSettingsIfDirty() {
+	if self.settingsDirty { self.SaveSettings() }
+}
+
 // Notice: scene manager update not included.
 func (self *Context) UpdateSystems() error {
 	var err error
@@ -76,3 +156,14 @@ func (self *Context) UpdateSystems() error {
 
 	return nil
 }
+
+// Keeps persistent data that failed to load from being overwritten
+// by the defaults used in its place.
+func setAside(name string) {
+	err := storage.SetAside(name)
+	if err != nil {
+		fmt.Printf("[%s not backed up, saving disabled: %s]\n", name, err)
+	} else {
+		fmt.Printf("[%s backed up as %s]\n", name, name + storage.BackupSuffix)
+	}
+}
@@ -15,7 +15,11 @@ import "github.com/tinne26/luckyfeet/src/game/material/in"
 import "github.com/tinne26/luckyfeet/src/game/material/au"
 import "github.com/tinne26/luckyfeet/src/game/material/scene/registry"
 import "github.com/tinne26/luckyfeet/src/game/material/scene/keys"
+import "github.com/tinne26/luckyfeet/src/game/scene/gamepadsetup"
 import "github.com/tinne26/luckyfeet/src/game/settings"
+import "github.com/tinne26/luckyfeet/src/game/replay"
+import "github.com/tinne26/luckyfeet/src/game/leaderboard"
+import "github.com/tinne26/luckyfeet/src/game/material/version"
 
 // asssert interface compliance
 var _ ebiten.Game = (*Game)(nil)
@@ -25,7 +29,6 @@ type Game struct {
 	ctx *context.Context
 	canvas *ebiten.Image
 	prevScreenFitMode settings.ScreenFitMode
-	showFPS bool
 }
 
 func New(filesys fs.FS) (*Game, error) {
@@ -41,6 +44,29 @@ func New(filesys fs.FS) (*Game, error) {
 	}, nil
 }
 
+// Loads a replay file so it can be watched from the level selection.
+func (self *Game) LoadReplay(path string) error {
+	rep, err := replay.LoadFile(path)
+	if err != nil { return err }
+	if rep.GameVersion != version.Game {
+		fmt.Printf("[Replay recorded on %s, running %s, playback may differ]\n", rep.GameVersion, version.Game)
+	}
+	self.ctx.State.Replay = rep
+	return nil
+}
+
+// Enables leaderboard submissions for clears, with the given
+// server URL and player name.
+func (self *Game) SetLeaderboard(serverURL, playerName string) error {
+	name, valid := leaderboard.CleanName(playerName)
+	if !valid {
+		return fmt.Errorf("invalid leaderboard name '%s' (up to %d letters, digits, '_', '-' or '.')", playerName, leaderboard.MaxNameLen)
+	}
+	self.ctx.State.Leaderboard = leaderboard.NewHTTPClient(serverURL)
+	self.ctx.State.PlayerName = name
+	return nil
+}
+
 func (self *Game) Layout(logicWinWidth, logicWinHeight int) (int, int) {
 	panic("using ebitengine >=v2.5.0 LayoutF()")
 	// scale := ebiten.DeviceScaleFactor()
@@ -61,15 +87,24 @@ func (self *Game) Update() error {
 	err = self.ctx.UpdateSystems()
 	if err != nil { return err }
 
+	// gamepads without any usable layout interrupt whatever is going on
+	_, settingUpGamepad := self.ctx.Scenes.Current().(*gamepadsetup.GamepadSetup)
+	if !settingUpGamepad && in.NeedsGamepadSetup(self.ctx.Input.Gamepad()) {
+		self.ctx.Scenes.Push(keys.GamepadSetup, self.ctx)
+	}
+
 	err = self.ctx.Scenes.Update(self.ctx)
 	if err != nil { return err }
+	self.ctx.Toasts.Update()
 
 	// audio start (should be somewhere else...)
 	if !self.ctx.Audio.IsActive(au.BgmMain) && au.IsContextReady() {
 		self.ctx.Audio.FadeIn(au.BgmMain, 0, 0, 0)
 	}
 
-	// global actions
+	// global actions (not while pressing everything on gamepad setup)
+	_, settingUpGamepad = self.ctx.Scenes.Current().(*gamepadsetup.GamepadSetup)
+	if settingUpGamepad { return nil }
 	if self.ctx.Input.Trigger(in.ActionFullscreen) {
 		if ebiten.IsFullscreen() {
 			fmt.Print("[Switching to windowed mode]\n")
@@ -80,7 +115,8 @@ func (self *Game) Update() error {
 		}
 	}
 	if self.ctx.Input.Trigger(in.ActionToggleFPS) {
-		self.showFPS = !self.showFPS
+		self.ctx.Settings.ShowFPS = !self.ctx.Settings.ShowFPS
+		self.ctx.SaveSettings()
 	}
 
 	// ...
@@ -96,9 +132,10 @@ func (self *Game) Draw(canvas *ebiten.Image) {
 	
 	// draw on logical canvas first
 	self.ctx.Scenes.DrawLogical(self.canvas, self.ctx)
+	self.ctx.Toasts.Draw(self.canvas)
 
 	// fps debug
-	if self.showFPS {
+	if self.ctx.Settings.ShowFPS {
 		fps := fmt.Sprintf("%.02f FPS", ebiten.ActualFPS())
 		text.CenterDrawAt(self.canvas, 320, 10, []string{fps}, color.RGBA{0, 0, 0, 255}, 1)
 	}
@@ -0,0 +1,37 @@
+package ghost
+
+// Visual state of the player for a single tick, enough to draw
+// a ghost of it later on.
+type Pose struct {
+	MapIndex uint8
+	X, Y int16 // top-left corner of the collision rect
+	Layer uint8
+	Left bool // facing direction
+	Anim uint8 // index into animations.Animations.List()
+	Frame uint8 // animation frame index
+}
+
+// A recorded run, with one pose per tick.
+type Run struct {
+	Character string // descriptor name
+	Poses []Pose
+}
+
+func (self *Run) Record(pose Pose) {
+	self.Poses = append(self.Poses, pose)
+}
+
+// Returns the pose for the given tick, starting from 0, or false
+// if the run was already over by then.
+func (self *Run) PoseAt(tick int) (Pose, bool) {
+	if tick < 0 || tick >= len(self.Poses) { return Pose{}, false }
+	return self.Poses[tick], true
+}
+
+func (self *Run) Ticks() int { return len(self.Poses) }
+
+// Reports whether the run is faster than the given one. Any run
+// is better than no run.
+func (self *Run) BeatsRun(other *Run) bool {
+	return other == nil || self.Ticks() < other.Ticks()
+}
@@ -0,0 +1,66 @@
+package ghost
+
+import "slices"
+import "testing"
+
+import "github.com/tinne26/luckyfeet/src/game/records"
+
+func TestPoseAt(t *testing.T) {
+	var run Run
+	for i := 0; i < 3; i++ {
+		run.Record(Pose{ X: int16(i), Frame: uint8(i) })
+	}
+
+	for i := 0; i < 3; i++ {
+		pose, found := run.PoseAt(i)
+		if !found { t.Fatalf("pose %d not found", i) }
+		if pose.X != int16(i) || pose.Frame != uint8(i) {
+			t.Fatalf("unexpected pose %d: %+v", i, pose)
+		}
+	}
+	for _, tick := range []int{ -1, 3, 100 } {
+		_, found := run.PoseAt(tick)
+		if found { t.Fatalf("expected no pose at tick %d", tick) }
+	}
+}
+
+func TestBeatsRun(t *testing.T) {
+	slow := &Run{ Poses: make([]Pose, 10) }
+	fast := &Run{ Poses: make([]Pose, 5) }
+	if !fast.BeatsRun(nil) { t.Fatal("any run should beat no run") }
+	if !fast.BeatsRun(slow) { t.Fatal("fast run should beat slow run") }
+	if slow.BeatsRun(fast) { t.Fatal("slow run shouldn't beat fast run") }
+	if fast.BeatsRun(fast) { t.Fatal("ties shouldn't replace the best run") }
+}
+
+func TestEncodeDecode(t *testing.T) {
+	var run Run
+	for i := 0; i < 300; i++ {
+		pose := Pose{ X: int16(40 + i/2), Y: int16(200 - i%40), Layer: 2, Anim: uint8(i/100), Frame: uint8(i/7 % 4) }
+		if i > 250 { pose = Pose{ MapIndex: 1, X: -3, Y: 500, Left: true } }
+		run.Record(pose)
+	}
+	runs := map[records.Key]*Run{
+		{ PackHash: 0xDEADBEEF12345678, StartMap: 1 }: &run,
+		{ PackHash: 7, StartMap: 0 }: &Run{ Character: "LUCKY" },
+	}
+	run.Character = "FLOPPY"
+
+	data := Encode(runs)
+	decoded, err := Decode(data)
+	if err != nil { t.Fatal(err) }
+	if len(decoded) != len(runs) { t.Fatalf("expected %d runs, got %d", len(runs), len(decoded)) }
+	for key, run := range runs {
+		got := decoded[key]
+		if got == nil || got.Character != run.Character || !slices.Equal(got.Poses, run.Poses) {
+			t.Fatalf("run %+v changed after decoding", key)
+		}
+	}
+
+	for _, n := range []int{ 0, 4, 12, len(data) - 1 } {
+		_, err := Decode(data[ : n])
+		if err == nil { t.Fatalf("expected error on data truncated at %d", n) }
+	}
+	_, err = Decode(append(data, 0))
+	if err == nil { t.Fatal("expected error on trailing data") }
+}
@@ -0,0 +1,141 @@
+package ghost
+
+import "sort"
+import "errors"
+import "io/fs"
+import "encoding/binary"
+
+import "github.com/tinne26/luckyfeet/src/game/records"
+import "github.com/tinne26/luckyfeet/src/game/storage"
+
+// Storage name for the best runs.
+const FileName = "ghosts.dat"
+
+// Binary format: "LFGH" magic, format version byte, uvarint number
+// of runs and then, for each run, its key (8 bytes pack hash, big
+// endian, and start map), character name (length prefixed) and
+// poses. Poses are run-length encoded as a uvarint number of runs
+// followed by (uvarint length, map index, layer, facing left, anim,
+// frame, varint x delta, varint y delta) entries, with deltas
+// relative to the previous pose.
+const magic = "LFGH"
+const formatVersion = 1
+
+// Two hours at 120 ticks per second, like replays.
+const maxPoses = 120*60*60*2
+
+var ErrInvalidData = errors.New("invalid ghosts data")
+
+// Loads the best runs from storage. If nothing was saved yet, an
+// empty map is returned.
+func Load() (map[records.Key]*Run, error) {
+	data, err := storage.Load(FileName)
+	if errors.Is(err, fs.ErrNotExist) { return make(map[records.Key]*Run), nil }
+	if err != nil { return nil, err }
+	return Decode(data)
+}
+
+func Save(runs map[records.Key]*Run) error {
+	return storage.Save(FileName, Encode(runs))
+}
+
+func Encode(runs map[records.Key]*Run) []byte {
+	keys := make([]records.Key, 0, len(runs))
+	for key, _ := range runs { keys = append(keys, key) }
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].PackHash != keys[j].PackHash { return keys[i].PackHash < keys[j].PackHash }
+		return keys[i].StartMap < keys[j].StartMap
+	})
+
+	data := make([]byte, 0, 256)
+	data = append(data, magic...)
+	data = append(data, formatVersion)
+	data = binary.AppendUvarint(data, uint64(len(keys)))
+	for _, key := range keys {
+		run := runs[key]
+		data = binary.BigEndian.AppendUint64(data, key.PackHash)
+		data = append(data, key.StartMap)
+		character := run.Character[ : min(len(run.Character), 255)]
+		data = append(data, uint8(len(character)))
+		data = append(data, character...)
+		data = appendPoses(data, run.Poses)
+	}
+	return data
+}
+
+func appendPoses(data []byte, poses []Pose) []byte {
+	var numRuns int
+	for i, _ := range poses {
+		if i == 0 || poses[i] != poses[i - 1] { numRuns += 1 }
+	}
+	data = binary.AppendUvarint(data, uint64(numRuns))
+	var prev Pose
+	for i := 0; i < len(poses); {
+		pose := poses[i]
+		runEnd := i + 1
+		for runEnd < len(poses) && poses[runEnd] == pose { runEnd += 1 }
+		data = binary.AppendUvarint(data, uint64(runEnd - i))
+		var left uint8
+		if pose.Left { left = 1 }
+		data = append(data, pose.MapIndex, pose.Layer, left, pose.Anim, pose.Frame)
+		data = binary.AppendVarint(data, int64(pose.X) - int64(prev.X))
+		data = binary.AppendVarint(data, int64(pose.Y) - int64(prev.Y))
+		prev = pose
+		i = runEnd
+	}
+	return data
+}
+
+func Decode(data []byte) (map[records.Key]*Run, error) {
+	if len(data) < len(magic) + 1 || string(data[ : len(magic)]) != magic {
+		return nil, ErrInvalidData
+	}
+	if data[len(magic)] != formatVersion { return nil, errors.New("unsupported ghosts format version") }
+	data = data[len(magic) + 1 : ]
+	numRuns, n := binary.Uvarint(data)
+	if n <= 0 { return nil, ErrInvalidData }
+	data = data[n : ]
+
+	runs := make(map[records.Key]*Run)
+	for i := uint64(0); i < numRuns; i++ {
+		if len(data) < 8 + 1 + 1 { return nil, ErrInvalidData }
+		key := records.Key{ PackHash: binary.BigEndian.Uint64(data), StartMap: data[8] }
+		nameLen := int(data[9])
+		data = data[10 : ]
+		if len(data) < nameLen { return nil, ErrInvalidData }
+		run := &Run{ Character: string(data[ : nameLen]) }
+		data = data[nameLen : ]
+		var err error
+		run.Poses, data, err = decodePoses(data)
+		if err != nil { return nil, err }
+		runs[key] = run
+	}
+	if len(data) != 0 { return nil, ErrInvalidData }
+	return runs, nil
+}
+
+func decodePoses(data []byte) ([]Pose, []byte, error) {
+	numRuns, n := binary.Uvarint(data)
+	if n <= 0 { return nil, nil, ErrInvalidData }
+	data = data[n : ]
+	var poses []Pose
+	var prev Pose
+	for run := uint64(0); run < numRuns; run++ {
+		runLen, n := binary.Uvarint(data)
+		if n <= 0 || runLen == 0 || len(data) < n + 5 { return nil, nil, ErrInvalidData }
+		if uint64(len(poses)) + runLen > maxPoses { return nil, nil, errors.New("ghost run is too long") }
+		fields := data[n : n + 5]
+		pose := Pose{ MapIndex: fields[0], Layer: fields[1], Left: fields[2] != 0, Anim: fields[3], Frame: fields[4] }
+		data = data[n + 5 : ]
+		dx, n := binary.Varint(data)
+		if n <= 0 { return nil, nil, ErrInvalidData }
+		data = data[n : ]
+		dy, n := binary.Varint(data)
+		if n <= 0 { return nil, nil, ErrInvalidData }
+		data = data[n : ]
+		pose.X, pose.Y = int16(int64(prev.X) + dx), int16(int64(prev.Y) + dy)
+		for i := uint64(0); i < runLen; i++ { poses = append(poses, pose) }
+		prev = pose
+	}
+	return poses, data, nil
+}
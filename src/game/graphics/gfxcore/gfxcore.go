@@ -16,6 +16,7 @@ type Graphics struct {
 	MenuMask *ebiten.Image
 	BackLightingSmall *ebiten.Image
 	BackLightingBig *ebiten.Image
+	Critters *ebiten.Image // sprite sheet for map entities
 
 	CarrotInUseMask *ebiten.Image
 	CarrotSelector *ebiten.Image
@@ -109,6 +110,12 @@ func New(filesys fs.FS) (*Graphics, error) {
 	if err != nil { return nil, err }
 	tiles[tcsts.MainGrassCornerFull], err = loadTileVariants(filesys, LayerMainPath + "grass_corner_full_")
 	if err != nil { return nil, err }
+	tiles[tcsts.MainSlope45], err = loadTileVariants(filesys, LayerMainPath + "slope45_")
+	if err != nil { return nil, err }
+	tiles[tcsts.MainSlope22Low], err = loadTileVariants(filesys, LayerMainPath + "slope22_low_")
+	if err != nil { return nil, err }
+	tiles[tcsts.MainSlope22High], err = loadTileVariants(filesys, LayerMainPath + "slope22_high_")
+	if err != nil { return nil, err }
 
 	tiles[tcsts.MainOrangePlatSingle], err = loadTileVariants(filesys, LayerMainPath + "carrot_orange_plat_single_")
 	if err != nil { return nil, err }
@@ -184,6 +191,12 @@ func New(filesys fs.FS) (*Graphics, error) {
 	if err != nil { return nil, err }
 	tiles[tcsts.FrontGrassCornerFull], err = loadTileVariants(filesys, LayerFrontPath + "grass_corner_full_")
 	if err != nil { return nil, err }
+	tiles[tcsts.FrontSlope45], err = loadTileVariants(filesys, LayerFrontPath + "slope45_")
+	if err != nil { return nil, err }
+	tiles[tcsts.FrontSlope22Low], err = loadTileVariants(filesys, LayerFrontPath + "slope22_low_")
+	if err != nil { return nil, err }
+	tiles[tcsts.FrontSlope22High], err = loadTileVariants(filesys, LayerFrontPath + "slope22_high_")
+	if err != nil { return nil, err }
 
 	const LayerSpecialPath = "assets/graphics/tiles/layer_special/"
 	tiles[tcsts.RaceGoal], err = loadTileVariants(filesys, LayerSpecialPath + "race_goal_")
@@ -235,11 +248,24 @@ func New(filesys fs.FS) (*Graphics, error) {
 	tiles[tcsts.TransferLeftC], err = loadTileVariants(filesys, LayerSpecialPath + "transfer_leftC_")
 	if err != nil { return nil, err }
 
+	tiles[tcsts.ZoneWindRight], err = loadTileVariants(filesys, LayerSpecialPath + "zone_wind_right_")
+	if err != nil { return nil, err }
+	tiles[tcsts.ZoneWindLeft], err = loadTileVariants(filesys, LayerSpecialPath + "zone_wind_left_")
+	if err != nil { return nil, err }
+	tiles[tcsts.ZoneUpdraft], err = loadTileVariants(filesys, LayerSpecialPath + "zone_updraft_")
+	if err != nil { return nil, err }
+	tiles[tcsts.EntityPatroller], err = loadTileVariants(filesys, LayerSpecialPath + "entity_patroller_")
+	if err != nil { return nil, err }
+	tiles[tcsts.EntityHopper], err = loadTileVariants(filesys, LayerSpecialPath + "entity_hopper_")
+	if err != nil { return nil, err }
+
 	// load other assets
 	backLightingSmall, err := loadImage(filesys, "assets/graphics/environment/back_lighting_small.png")
 	if err != nil { return nil, err }
 	backLightingBig, err := loadImage(filesys, "assets/graphics/environment/back_lighting_big.png")
 	if err != nil { return nil, err }
+	critters, err := loadImage(filesys, "assets/graphics/creatures/critters.png")
+	if err != nil { return nil, err }
 
 	const UIGraphicsPath = "assets/graphics/ui/"
 	carrotInUseMask, err := loadImage(filesys, UIGraphicsPath + "carrot_in_use_mask.png")
@@ -264,6 +290,7 @@ func New(filesys fs.FS) (*Graphics, error) {
 		MenuMask: menuMask,
 		BackLightingSmall: backLightingSmall,
 		BackLightingBig: backLightingBig,
+		Critters: critters,
 		CarrotInUseMask: carrotInUseMask,
 		CarrotSelector: carrotSelector,
 		CarrotNone: carrotNone,
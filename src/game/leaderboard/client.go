@@ -0,0 +1,101 @@
+package leaderboard
+
+import "io"
+import "fmt"
+import "sync"
+import "time"
+import "bytes"
+import "strings"
+import "net/url"
+import "net/http"
+import "encoding/json"
+
+import "github.com/tinne26/luckyfeet/src/game/replay"
+
+var _ Client = (*HTTPClient)(nil)
+
+// Requests taking longer than this are considered failed.
+const Timeout = 8*time.Second
+
+// Client for Server, or any server following the same API.
+type HTTPClient struct {
+	BaseURL string // e.g. "http://localhost:8426"
+	HTTP *http.Client
+}
+
+func NewHTTPClient(baseURL string) *HTTPClient {
+	return &HTTPClient{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		HTTP: &http.Client{ Timeout: Timeout },
+	}
+}
+
+func (self *HTTPClient) Submit(name string, run *replay.Replay) (Entry, error) {
+	var entry Entry
+	query := url.Values{ "name": { name } }
+	endpoint := self.BaseURL + "/submit?" + query.Encode()
+	response, err := self.HTTP.Post(endpoint, "application/octet-stream", bytes.NewReader(run.Encode()))
+	if err != nil { return entry, err }
+	err = decodeResponse(response, &entry)
+	return entry, err
+}
+
+func (self *HTTPClient) Top(packHash uint64, startMap uint8, n int) ([]Entry, error) {
+	var entries []Entry
+	query := url.Values{
+		"pack": { fmt.Sprintf("%016x", packHash) },
+		"map": { fmt.Sprint(startMap) },
+		"n": { fmt.Sprint(n) },
+	}
+	response, err := self.HTTP.Get(self.BaseURL + "/top?" + query.Encode())
+	if err != nil { return nil, err }
+	err = decodeResponse(response, &entries)
+	return entries, err
+}
+
+func decodeResponse(response *http.Response, target any) error {
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(response.Body, 256))
+		if response.StatusCode == http.StatusUnprocessableEntity {
+			return fmt.Errorf("%w: %s", ErrRejected, strings.TrimSpace(string(msg)))
+		}
+		return fmt.Errorf("leaderboard server error: %s", response.Status)
+	}
+	return json.NewDecoder(response.Body).Decode(target)
+}
+
+// Submits a run in the background, so the game never waits on the
+// network. Results can be polled with [Submission.Result]().
+type Submission struct {
+	mutex sync.Mutex
+	done bool
+	entry Entry
+	top []Entry
+	err error
+}
+
+// Submits the run and then fetches the top n entries for its level.
+func SubmitAsync(client Client, name string, run *replay.Replay, n int) *Submission {
+	submission := &Submission{}
+	go func() {
+		entry, err := client.Submit(name, run)
+		var top []Entry
+		if err == nil { // top entries are a bonus, errors are ignored
+			top, _ = client.Top(run.PackHash, run.StartMap, n)
+		}
+
+		submission.mutex.Lock()
+		defer submission.mutex.Unlock()
+		submission.done = true
+		submission.entry, submission.top, submission.err = entry, top, err
+	}()
+	return submission
+}
+
+// Returns done = false while the submission is still in progress.
+func (self *Submission) Result() (entry Entry, top []Entry, done bool, err error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.entry, self.top, self.done, self.err
+}
@@ -0,0 +1,53 @@
+package leaderboard
+
+import "fmt"
+import "errors"
+import "strings"
+
+import "github.com/tinne26/luckyfeet/src/game/replay"
+
+// Leaderboards keep the best verified clear time of each player
+// for each level, identified by level pack hash and start map.
+// See Server for the reference implementation and HTTPClient for
+// the game side.
+type Entry struct {
+	Name string `json:"name"`
+	ClearTicks int `json:"clearTicks"`
+	Character string `json:"characterName"` // descriptor name, see replay.Replay
+	GameVersion string `json:"gameVersion"`
+	Rank int `json:"rank,omitempty"` // 1-based, only set on responses
+}
+
+type Client interface {
+	// Submits a finished run, which the server verifies before
+	// accepting it. Returns the player's entry for the level,
+	// which can be an older and faster run.
+	Submit(name string, run *replay.Replay) (Entry, error)
+
+	// Returns up to n entries for the given level, fastest first.
+	Top(packHash uint64, startMap uint8, n int) ([]Entry, error)
+}
+
+// Returned when the server refuses a run (invalid name, failed
+// verification, etc.), as opposed to network or server errors.
+var ErrRejected = errors.New("run rejected by the leaderboard")
+
+const MaxNameLen = 12
+
+// Names are limited to characters the game font can draw.
+// Lowercase letters are converted to uppercase.
+func CleanName(name string) (string, bool) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "" || len(name) > MaxNameLen { return "", false }
+	for _, char := range name {
+		if char >= 'A' && char <= 'Z' { continue }
+		if char >= '0' && char <= '9' { continue }
+		if char == '_' || char == '-' || char == '.' { continue }
+		return "", false
+	}
+	return name, true
+}
+
+func boardKey(packHash uint64, startMap uint8) string {
+	return fmt.Sprintf("%016x-%d", packHash, startMap)
+}
@@ -0,0 +1,133 @@
+package leaderboard
+
+import "time"
+import "errors"
+import "testing"
+import "net/http"
+import "net/http/httptest"
+import "path/filepath"
+
+import "github.com/tinne26/luckyfeet/src/game/replay"
+
+// Serves requests with the handler directly, so tests don't need
+// to open sockets (which also allows running them on wasm).
+type handlerTransport struct { handler http.Handler }
+func (self handlerTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	self.handler.ServeHTTP(recorder, request)
+	return recorder.Result(), nil
+}
+
+type failingTransport struct {}
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("unreachable")
+}
+
+// Fake verification: the run's ticks are its clear time, and runs
+// claiming a different clear time fail.
+func fakeVerify(run *replay.Replay) (int, error) {
+	if run.ClearTicks != len(run.Ticks) { return 0, errors.New("desync") }
+	return len(run.Ticks), nil
+}
+
+func newTestRun(packHash uint64, ticks int) *replay.Replay {
+	run := &replay.Replay{ PackHash: packHash, StartMap: 1, Character: "LUCKY", ClearTicks: ticks }
+	for i := 0; i < ticks; i++ { run.Append(replay.NewTick(1.0, 0)) }
+	return run
+}
+
+func newTestClient(server http.Handler) *HTTPClient {
+	client := NewHTTPClient("http://leaderboard.test/")
+	client.HTTP.Transport = handlerTransport{ server }
+	return client
+}
+
+func TestSubmitAndTop(t *testing.T) {
+	server := NewServer(fakeVerify)
+	server.MaxEntries = 3
+	client := newTestClient(server)
+	submissions := []struct{ name string; ticks int; wantRank int }{
+		{ "bunny", 500, 1 },
+		{ "HARE", 400, 1 },
+		{ "BUNNY", 450, 2 }, // improved
+		{ "HARE", 420, 1 }, // slower, keeps older entry
+		{ "CARROT", 450, 3 }, // tie, ranks after the earlier entry
+		{ "SLOWPOKE", 900, 0 }, // doesn't make it
+	}
+	for _, submission := range submissions {
+		entry, err := client.Submit(submission.name, newTestRun(7, submission.ticks))
+		if err != nil { t.Fatalf("%s: %s", submission.name, err) }
+		if entry.Rank != submission.wantRank {
+			t.Fatalf("%s: expected rank %d, got %+v", submission.name, submission.wantRank, entry)
+		}
+	}
+
+	top, err := client.Top(7, 1, 10)
+	if err != nil { t.Fatal(err) }
+	want := []Entry{
+		{ Name: "HARE", ClearTicks: 400, Character: "LUCKY", Rank: 1 },
+		{ Name: "BUNNY", ClearTicks: 450, Character: "LUCKY", Rank: 2 },
+		{ Name: "CARROT", ClearTicks: 450, Character: "LUCKY", Rank: 3 },
+	}
+	if len(top) != len(want) { t.Fatalf("expected %d entries, got %+v", len(want), top) }
+	for i, _ := range want {
+		if top[i] != want[i] { t.Fatalf("entry %d: expected %+v, got %+v", i, want[i], top[i]) }
+	}
+
+	// other levels are independent
+	top, err = client.Top(7, 0, 10)
+	if err != nil || len(top) != 0 { t.Fatalf("expected empty board, got %+v (err = %v)", top, err) }
+}
+
+func TestRejections(t *testing.T) {
+	client := newTestClient(NewServer(fakeVerify))
+	run := newTestRun(7, 100)
+	run.ClearTicks = 90
+	_, err := client.Submit("BUNNY", run)
+	if !errors.Is(err, ErrRejected) { t.Fatalf("expected rejection, got %v", err) }
+	_, err = client.Submit("NOT A NAME", newTestRun(7, 100))
+	if !errors.Is(err, ErrRejected) { t.Fatalf("expected rejection, got %v", err) }
+}
+
+func TestUnavailable(t *testing.T) {
+	client := NewHTTPClient("http://leaderboard.test")
+	client.HTTP.Transport = failingTransport{}
+	submission := SubmitAsync(client, "BUNNY", newTestRun(7, 100), 5)
+	for i := 0; i < 1000; i++ {
+		_, _, done, err := submission.Result()
+		if !done {
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		if err == nil || errors.Is(err, ErrRejected) { t.Fatalf("expected network error, got %v", err) }
+		return
+	}
+	t.Fatal("submission didn't finish")
+}
+
+func TestServerFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leaderboard.json")
+	server := NewServer(fakeVerify)
+	err := server.UseFile(path)
+	if err != nil { t.Fatal(err) }
+	_, err = newTestClient(server).Submit("BUNNY", newTestRun(7, 100))
+	if err != nil { t.Fatal(err) }
+
+	reloaded := NewServer(fakeVerify)
+	err = reloaded.UseFile(path)
+	if err != nil { t.Fatal(err) }
+	top, err := newTestClient(reloaded).Top(7, 1, 1)
+	if err != nil || len(top) != 1 || top[0].Name != "BUNNY" {
+		t.Fatalf("expected saved entry, got %+v (err = %v)", top, err)
+	}
+}
+
+func TestCleanName(t *testing.T) {
+	for name, want := range map[string]string{ " lucky_7 ": "LUCKY_7", "A.B-C": "A.B-C" } {
+		got, valid := CleanName(name)
+		if !valid || got != want { t.Fatalf("expected '%s' for '%s', got '%s'", want, name, got) }
+	}
+	for _, name := range []string{ "", "TWELVE_CHARS+", "SPACE BAR", "ÑU" } {
+		if _, valid := CleanName(name); valid { t.Fatalf("expected '%s' to be invalid", name) }
+	}
+}
@@ -0,0 +1,176 @@
+package leaderboard
+
+import "io"
+import "os"
+import "fmt"
+import "sort"
+import "sync"
+import "errors"
+import "strconv"
+import "net/http"
+import "io/fs"
+import "encoding/json"
+
+import "github.com/tinne26/luckyfeet/src/game/replay"
+
+// Simulates the run and returns its clear ticks, or an error if
+// the run is invalid or can't be verified (see race.Verify).
+type VerifyFunc func(run *replay.Replay) (int, error)
+
+const DefaultMaxEntries = 100
+const maxTopEntries = 100
+const maxReplaySize = 1 << 20
+
+// Reference leaderboard server, small enough to run locally or
+// self-host. Entries are kept in memory and optionally saved to
+// a JSON file after each accepted submission.
+//
+// API:
+//   POST /submit?name=NAME with an encoded replay as the body,
+//        returns the player's entry for the level.
+//   GET  /top?pack=HASH&map=INDEX&n=COUNT, returns the entries.
+// Runs that fail verification get a 422 status code.
+type Server struct {
+	Verify VerifyFunc
+	MaxEntries int // per level, slower entries are dropped
+
+	mutex sync.Mutex
+	boards map[string][]Entry
+	path string
+}
+
+func NewServer(verify VerifyFunc) *Server {
+	return &Server{
+		Verify: verify,
+		MaxEntries: DefaultMaxEntries,
+		boards: make(map[string][]Entry),
+	}
+}
+
+// Loads the entries from the given file, if it exists, and keeps
+// saving them there from then on.
+func (self *Server) UseFile(path string) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) { return nil }
+	if err != nil { return err }
+	return json.Unmarshal(data, &self.boards)
+}
+
+func (self *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// allow browser builds to use the server from any origin
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/submit" && r.Method == http.MethodPost:
+		self.serveSubmit(w, r)
+	case r.URL.Path == "/top" && r.Method == http.MethodGet:
+		self.serveTop(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (self *Server) serveSubmit(w http.ResponseWriter, r *http.Request) {
+	name, valid := CleanName(r.URL.Query().Get("name"))
+	if !valid {
+		http.Error(w, "invalid name", http.StatusUnprocessableEntity)
+		return
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxReplaySize))
+	if err != nil {
+		http.Error(w, "replay too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	run, err := replay.Decode(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	clearTicks, err := self.Verify(run)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	entry := Entry{ Name: name, ClearTicks: clearTicks, Character: run.Character, GameVersion: run.GameVersion }
+	entry, err = self.add(boardKey(run.PackHash, run.StartMap), entry)
+	if err != nil { fmt.Printf("[Leaderboard not saved: %s]\n", err) }
+	writeJSON(w, entry)
+}
+
+func (self *Server) serveTop(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	packHash, err1 := strconv.ParseUint(query.Get("pack"), 16, 64)
+	startMap, err2 := strconv.ParseUint(query.Get("map"), 10, 8)
+	n, err3 := strconv.Atoi(query.Get("n"))
+	if err1 != nil || err2 != nil || err3 != nil || n < 0 {
+		http.Error(w, "invalid query", http.StatusBadRequest)
+		return
+	}
+
+	self.mutex.Lock()
+	entries := self.boards[boardKey(packHash, uint8(startMap))]
+	entries = append([]Entry{}, entries[ : min(n, maxTopEntries, len(entries))]...)
+	self.mutex.Unlock()
+	for i, _ := range entries { entries[i].Rank = i + 1 }
+	writeJSON(w, entries)
+}
+
+// Adds the entry unless the player already has a faster one, and
+// returns the player's entry with its rank. The rank is 0 if the
+// entry didn't make it into the board.
+func (self *Server) add(key string, entry Entry) (Entry, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	entries := self.boards[key]
+	for i, _ := range entries {
+		if entries[i].Name != entry.Name { continue }
+		if entries[i].ClearTicks <= entry.ClearTicks {
+			entry = entries[i]
+			entry.Rank = i + 1
+			return entry, nil
+		}
+		entries = append(entries[ : i], entries[i + 1 : ]...)
+		break
+	}
+
+	// insert after entries with the same time, first come first served
+	index := sort.Search(len(entries), func(i int) bool {
+		return entries[i].ClearTicks > entry.ClearTicks
+	})
+	entries = append(entries, Entry{})
+	copy(entries[index + 1 : ], entries[index : ])
+	entries[index] = entry
+	if self.MaxEntries > 0 && len(entries) > self.MaxEntries {
+		entries = entries[ : self.MaxEntries]
+	}
+	self.boards[key] = entries
+	if index < len(entries) { entry.Rank = index + 1 }
+	return entry, self.save()
+}
+
+func (self *Server) save() error {
+	if self.path == "" { return nil }
+	data, err := json.Marshal(self.boards)
+	if err != nil { return err }
+	tmpPath := self.path + ".tmp"
+	err = os.WriteFile(tmpPath, data, 0644)
+	if err != nil { return err }
+	return os.Rename(tmpPath, self.path)
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(value)
+	if err != nil { fmt.Printf("[Leaderboard response failed: %s]\n", err) }
+}
@@ -12,19 +12,33 @@ type Animations struct {
 	Idle *motion.Animation
 	Running *motion.Animation
 	InAir *motion.Animation
+	WallSlide *motion.Animation
+	WallJump *motion.Animation
+	Dash *motion.Animation
 }
 
-func New(filesys fs.FS) (*Animations, error) {
-	// load mc animations image
-	const CreaturesPath = "assets/graphics/creatures/"
-	mc, err := loadImage(filesys, CreaturesPath + "mc.png")
+// Returns the animations in a fixed order, so they can be
+// referenced by index (e.g. in ghost runs).
+func (self *Animations) List() [6]*motion.Animation {
+	return [6]*motion.Animation{ self.Idle, self.Running, self.InAir, self.WallSlide, self.WallJump, self.Dash }
+}
+
+// Loads the animations from a character sprite sheet. All sheets
+// share the layout of creatures/mc.png, but the frame size can vary.
+func New(filesys fs.FS, sheetPath string, frameWidth, frameHeight int) (*Animations, error) {
+	sheet, err := loadImage(filesys, sheetPath)
 	if err != nil { return nil, err }
+	var frame = func(col, row int) *ebiten.Image {
+		ox, oy := frameWidth*col, frameHeight*row
+		rect := image.Rect(ox, oy, ox + frameWidth, oy + frameHeight)
+		return sheet.SubImage(rect).(*ebiten.Image)
+	}
 
 	anims := &Animations{}
 	anims.Idle = motion.NewAnimation("idle")
-	idle1 := frame(mc, 0, 0)
-	idle2 := frame(mc, 2, 0)
-	idleFeet := frame(mc, 1, 0)
+	idle1 := frame(0, 0)
+	idle2 := frame(2, 0)
+	idleFeet := frame(1, 0)
 	anims.Idle.AddFrame(idle1, 160)
 	anims.Idle.AddFrame(idle2, 160)
 	anims.Idle.AddFrame(idle1, 160)
@@ -41,12 +55,12 @@ func New(filesys fs.FS) (*Animations, error) {
 	anims.Idle.AddFrame(idle2, 160)
 
 	anims.Running = motion.NewAnimation("running")
-	walk2Run := frame(mc, 3, 0)
-	run1 := frame(mc, 0, 1)
-	run2 := frame(mc, 1, 1)
-	run3 := frame(mc, 2, 1)
-	run4 := frame(mc, 3, 1)
-	run5 := frame(mc, 4, 1)
+	walk2Run := frame(3, 0)
+	run1 := frame(0, 1)
+	run2 := frame(1, 1)
+	run3 := frame(2, 1)
+	run4 := frame(3, 1)
+	run5 := frame(4, 1)
 	anims.Running.AddFrameWithSfx(walk2Run, 10, motion.SfxLowStep)
 	anims.Running.AddFrameWithSfx(run1, 18, motion.SfxStep)
 	anims.Running.AddFrameWithSfx(run2, 18, motion.SfxLowStep)
@@ -56,9 +70,16 @@ func New(filesys fs.FS) (*Animations, error) {
 	anims.Running.SetLoopStart(1)
 
 	anims.InAir = motion.NewAnimation("air")
-	air1 := frame(mc, 0, 2)
+	air1 := frame(0, 2)
 	anims.InAir.AddFrame(air1, 255)
 
+	anims.WallSlide = motion.NewAnimation("wall slide")
+	anims.WallSlide.AddFrame(frame(3, 2), 255)
+	anims.WallJump = motion.NewAnimation("wall jump")
+	anims.WallJump.AddFrame(frame(4, 2), 255)
+	anims.Dash = motion.NewAnimation("dash")
+	anims.Dash.AddFrame(frame(1, 2), 255)
+
 	return anims, nil
 }
 
@@ -69,10 +90,3 @@ func loadImage(filesys fs.FS, filepath string) (*ebiten.Image, error) {
 	if err != nil { return nil, err }
 	return ebiten.NewImageFromImage(img), nil
 }
-
-func frame(img *ebiten.Image, col, row int) *ebiten.Image {
-	const FrameWidth, FrameHeight = 15, 35
-	ox, oy := FrameWidth*col, FrameHeight*row
-	rect := image.Rect(ox, oy, ox + FrameWidth, oy + FrameHeight)
-	return img.SubImage(rect).(*ebiten.Image)
-}
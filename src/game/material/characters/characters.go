@@ -0,0 +1,38 @@
+//go:build !headless
+
+package characters
+
+import "io/fs"
+
+import "github.com/tinne26/luckyfeet/src/game/material/animations"
+
+// Playable characters are defined by a sprite sheet and a small
+// *.char descriptor in CreaturesPath. See [ParseDescriptor]() for
+// the format. Characters are listed in descriptor file name order,
+// and the first one is the default. Replays and ghosts refer to
+// them by name instead, see [IndexByName]().
+type Character struct {
+	Descriptor
+	Animations *animations.Animations
+}
+
+func Load(filesys fs.FS) ([]*Character, error) {
+	descs, err := LoadDescriptors(filesys)
+	if err != nil { return nil, err }
+
+	chars := make([]*Character, 0, len(descs))
+	for _, desc := range descs {
+		anims, err := animations.New(filesys, CreaturesPath + desc.Sheet, desc.FrameWidth, desc.FrameHeight)
+		if err != nil { return nil, descError(desc.Sheet, err) }
+		chars = append(chars, &Character{ Descriptor: desc, Animations: anims })
+	}
+	return chars, nil
+}
+
+// Like [IndexByName](), but for loaded characters.
+func IndexOf(chars []*Character, name string) int {
+	for i, char := range chars {
+		if char.Name == name { return i }
+	}
+	return -1
+}
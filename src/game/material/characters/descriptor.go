@@ -0,0 +1,154 @@
+package characters
+
+import "fmt"
+import "path"
+import "image"
+import "io/fs"
+import "errors"
+import "strings"
+import "strconv"
+
+import "github.com/tinne26/luckyfeet/src/game/player/physics"
+
+const CreaturesPath = "assets/graphics/creatures/"
+
+// Character descriptors are plain text files with one "key: value"
+// entry per line. Empty lines and lines starting with '#' are
+// ignored. Example:
+//
+//   name: LUCKY
+//   sheet: mc.png          # relative to CreaturesPath
+//   frame: 15 35           # frame width and height
+//   box: 9 28 3 7          # collision width, height, x and y offsets
+//   light-right: 7 18      # optional, frame center by default
+//   light-left: 6 18       # optional, frame center by default
+//   profile: floaty        # optional, map preset is used otherwise
+//
+// Sprite sheets must follow the creatures/mc.png layout.
+type Descriptor struct {
+	Name string
+	Sheet string
+	FrameWidth, FrameHeight int
+	Box physics.Box
+	LightRight image.Point
+	LightLeft image.Point
+	Preset uint8
+	HasPreset bool
+}
+
+// Returns the physics profile for the character on a map with
+// the given preset. Characters with their own preset ignore it.
+func (self *Descriptor) Profile(mapPreset uint8) physics.Profile {
+	if self.HasPreset { return physics.PresetProfile(self.Preset) }
+	return physics.PresetProfile(mapPreset)
+}
+
+// Loads the descriptors of all playable characters, in the same
+// order as [Load](), but without any graphics. Also available on
+// headless builds.
+func LoadDescriptors(filesys fs.FS) ([]Descriptor, error) {
+	paths, err := fs.Glob(filesys, CreaturesPath + "*.char")
+	if err != nil { return nil, err }
+	if len(paths) == 0 { return nil, fs.ErrNotExist }
+
+	descs := make([]Descriptor, 0, len(paths))
+	for _, descPath := range paths {
+		data, err := fs.ReadFile(filesys, descPath)
+		if err != nil { return nil, err }
+		desc, err := ParseDescriptor(string(data))
+		if err != nil { return nil, descError(descPath, err) }
+		if IndexByName(descs, desc.Name) != -1 {
+			return nil, descError(descPath, fmt.Errorf("duplicate character name '%s'", desc.Name))
+		}
+		descs = append(descs, desc)
+	}
+	return descs, nil
+}
+
+// Returns the index of the descriptor with the given name, or -1
+// if there's none. Names identify characters in replays, as the
+// descriptor order can change when adding new characters.
+func IndexByName(descs []Descriptor, name string) int {
+	for i, _ := range descs {
+		if descs[i].Name == name { return i }
+	}
+	return -1
+}
+
+func ParseDescriptor(data string) (Descriptor, error) {
+	var desc Descriptor
+	var hasFrame, hasBox, hasLightRight, hasLightLeft bool
+	for i, line := range strings.Split(data, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		line = strings.TrimSpace(line)
+		if line == "" { continue }
+		key, value, found := strings.Cut(line, ":")
+		if !found { return desc, fmt.Errorf("line %d: expected \"key: value\"", i + 1) }
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		var err error
+		switch key {
+		case "name":
+			desc.Name = strings.ToUpper(value)
+		case "sheet":
+			desc.Sheet = value
+		case "frame":
+			err = parseInts(value, &desc.FrameWidth, &desc.FrameHeight)
+			hasFrame = true
+		case "box":
+			box := &desc.Box
+			err = parseInts(value, &box.Width, &box.Height, &box.XOffset, &box.YOffset)
+			hasBox = true
+		case "light-right":
+			err = parseInts(value, &desc.LightRight.X, &desc.LightRight.Y)
+			hasLightRight = true
+		case "light-left":
+			err = parseInts(value, &desc.LightLeft.X, &desc.LightLeft.Y)
+			hasLightLeft = true
+		case "profile":
+			desc.Preset, desc.HasPreset = presetByName(value)
+			if !desc.HasPreset { err = fmt.Errorf("unknown profile '%s'", value) }
+		default:
+			err = fmt.Errorf("unknown key '%s'", key)
+		}
+		if err != nil { return desc, fmt.Errorf("line %d: %w", i + 1, err) }
+	}
+
+	// validate and set defaults
+	if desc.Name == "" { return desc, errors.New("missing character name") }
+	if desc.Sheet == "" { return desc, errors.New("missing character sheet") }
+	if !hasFrame || desc.FrameWidth <= 0 || desc.FrameHeight <= 0 {
+		return desc, errors.New("missing or invalid frame size")
+	}
+	if !hasBox || desc.Box.Width < 4 || desc.Box.Height < 8 {
+		return desc, errors.New("missing or invalid collision box")
+	}
+	center := image.Pt(desc.FrameWidth/2, desc.FrameHeight/2)
+	if !hasLightRight { desc.LightRight = center }
+	if !hasLightLeft  { desc.LightLeft  = center }
+	return desc, nil
+}
+
+func parseInts(value string, targets ...*int) error {
+	fields := strings.Fields(value)
+	if len(fields) != len(targets) {
+		return fmt.Errorf("expected %d values, got %d", len(targets), len(fields))
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 { return fmt.Errorf("invalid value '%s'", field) }
+		*targets[i] = n
+	}
+	return nil
+}
+
+func presetByName(name string) (uint8, bool) {
+	for preset := uint8(0); preset < physics.PresetCountSentinel; preset++ {
+		if strings.EqualFold(physics.PresetName(preset), name) { return preset, true }
+	}
+	return 0, false
+}
+
+func descError(descPath string, err error) error {
+	return &fs.PathError{ Op: "load character", Path: path.Base(descPath), Err: err }
+}
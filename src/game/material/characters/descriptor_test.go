@@ -0,0 +1,88 @@
+package characters
+
+import "os"
+import "fmt"
+import "io/fs"
+import "image"
+import "testing"
+import "testing/fstest"
+
+import "github.com/tinne26/luckyfeet/src/game/player/physics"
+
+func TestParseDescriptor(t *testing.T) {
+	desc, err := ParseDescriptor(`
+		# comment line
+		name: Test
+		sheet: test.png  # trailing comment
+		frame: 16 32
+		box: 10 24 3 8
+		light-left: 5 17
+		profile: Tight
+	`)
+	if err != nil { t.Fatal(err) }
+	expected := Descriptor{
+		Name: "TEST",
+		Sheet: "test.png",
+		FrameWidth: 16, FrameHeight: 32,
+		Box: physics.Box{ Width: 10, Height: 24, XOffset: 3, YOffset: 8 },
+		LightRight: image.Pt(8, 16),
+		LightLeft: image.Pt(5, 17),
+		Preset: physics.PresetTight,
+		HasPreset: true,
+	}
+	if desc != expected {
+		t.Fatalf("expected %+v, got %+v", expected, desc)
+	}
+
+	invalid := []string{
+		"name: A\nsheet: a.png\nframe: 15 35",            // missing box
+		"name: A\nsheet: a.png\nframe: 15\nbox: 9 28 3 7", // bad frame
+		"name: A\nsheet: a.png\nframe: 15 35\nbox: 9 28 3 7\nprofile: bouncy",
+		"name: A\nsheet: a.png\nframe: 15 35\nbox: 9 28 3 7\ncolor: red",
+		"name A",
+	}
+	for i, data := range invalid {
+		_, err := ParseDescriptor(data)
+		if err == nil { t.Fatalf("invalid descriptor #%d didn't fail", i) }
+	}
+}
+
+func TestAssetDescriptors(t *testing.T) {
+	filesys := os.DirFS("../../../..")
+	paths, err := fs.Glob(filesys, CreaturesPath + "*.char")
+	if err != nil { t.Fatal(err) }
+	if len(paths) < 2 { t.Fatalf("expected at least 2 characters, found %d", len(paths)) }
+	for _, path := range paths {
+		data, err := fs.ReadFile(filesys, path)
+		if err != nil { t.Fatal(err) }
+		desc, err := ParseDescriptor(string(data))
+		if err != nil { t.Fatalf("%s: %s", path, err) }
+		_, err = fs.Stat(filesys, CreaturesPath + desc.Sheet)
+		if err != nil { t.Fatalf("%s: %s", path, err) }
+	}
+
+	// the default character must match the default physics box
+	data, err := fs.ReadFile(filesys, paths[0])
+	if err != nil { t.Fatal(err) }
+	desc, _ := ParseDescriptor(string(data))
+	if desc.Box != physics.DefaultBox {
+		t.Fatalf("expected default character box %v, got %v", physics.DefaultBox, desc.Box)
+	}
+}
+
+func TestDuplicateNames(t *testing.T) {
+	desc := "name: %s\nsheet: test.png\nframe: 15 35\nbox: 9 28 3 7\n"
+	filesys := fstest.MapFS{
+		CreaturesPath + "a.char": { Data: []byte(fmt.Sprintf(desc, "A")) },
+		CreaturesPath + "b.char": { Data: []byte(fmt.Sprintf(desc, "B")) },
+	}
+	descs, err := LoadDescriptors(filesys)
+	if err != nil { t.Fatal(err) }
+	if IndexByName(descs, "B") != 1 || IndexByName(descs, "C") != -1 {
+		t.Fatal("unexpected descriptor lookup results")
+	}
+
+	filesys[CreaturesPath + "c.char"] = &fstest.MapFile{ Data: []byte(fmt.Sprintf(desc, "a")) }
+	_, err = LoadDescriptors(filesys)
+	if err == nil { t.Fatal("expected error on duplicate character names") }
+}
@@ -14,6 +14,7 @@ const (
 	ActionUseCarrot
 	ActionNextCarrot
 	ActionPrevCarrot
+	ActionDash
 	
 	// --- editor bs ---
 	ActionModKey
@@ -0,0 +1,153 @@
+package in
+
+import "fmt"
+import "bufio"
+import "bytes"
+import "errors"
+import "strings"
+import "io/fs"
+
+import "github.com/tinne26/luckyfeet/src/lib/input"
+import "github.com/tinne26/luckyfeet/src/game/storage"
+
+// Storage name for the user bindings.
+const BindingsFileName = "bindings.txt"
+
+const bindingsHeader = "luckyfeet bindings v1"
+
+var ErrInvalidBindings = errors.New("invalid bindings data")
+
+// Where an action is used. Bindings can only conflict within
+// the same group, or with global actions.
+type Group uint8
+const (
+	GroupGlobal Group = iota
+	GroupMenu
+	GroupRace
+	GroupEditor
+)
+
+type Binding struct {
+	Action input.TriggerAction
+	ID string // persisted, must never change
+	Name string // shown on the rebind menu
+	Group Group
+	Essential bool // can't be left unassigned, menus need it
+}
+
+// Actions that can be rebound, in listing order. ActionMirrorTile
+// is not included, as mirroring uses ActionModKey and rotations.
+var Bindings = []Binding{
+	{ ActionJump, "jump", "JUMP", GroupRace, false },
+	{ ActionDash, "dash", "DASH", GroupRace, false },
+	{ ActionUseCarrot, "use_carrot", "USE CARROT", GroupRace, false },
+	{ ActionPrevCarrot, "prev_carrot", "PREV CARROT", GroupRace, false },
+	{ ActionNextCarrot, "next_carrot", "NEXT CARROT", GroupRace, false },
+	{ ActionMenu, "menu", "MENU", GroupGlobal, true },
+	{ ActionMenuBrowserAlt, "menu_alt", "MENU (BROWSER)", GroupGlobal, false },
+	{ ActionConfirm, "confirm", "CONFIRM", GroupMenu, true },
+	{ ActionBack, "back", "BACK", GroupMenu, true },
+	{ ActionFullscreen, "fullscreen", "FULLSCREEN", GroupGlobal, false },
+	{ ActionToggleFPS, "toggle_fps", "SHOW FPS", GroupGlobal, false },
+	{ ActionModKey, "editor_mod", "EDITOR MOD", GroupEditor, false },
+	{ ActionNextTile, "next_tile", "NEXT BLOCK", GroupEditor, false },
+	{ ActionPrevTile, "prev_tile", "PREV BLOCK", GroupEditor, false },
+	{ ActionNextTileGroup, "next_tile_group", "NEXT GROUP", GroupEditor, false },
+	{ ActionPrevTileGroup, "prev_tile_group", "PREV GROUP", GroupEditor, false },
+	{ ActionTileVariation, "tile_variation", "BLOCK VARIATION", GroupEditor, false },
+	{ ActionRotateTileRight, "rotate_right", "ROTATE RIGHT", GroupEditor, false },
+	{ ActionRotateTileLeft, "rotate_left", "ROTATE LEFT", GroupEditor, false },
+}
+
+// Returns the binding for the given action, or nil if it can't
+// be rebound.
+func FindBinding(action input.TriggerAction) *Binding {
+	for i, _ := range Bindings {
+		if Bindings[i].Action == action { return &Bindings[i] }
+	}
+	return nil
+}
+
+// Goes back to the default bindings. They aren't saved.
+func ResetBindings(kbgp *input.KBGP) {
+	kbgp.Keyboard().Config().DeleteAllTriggerActions()
+	kbgp.Gamepad().Config().DeleteAllTriggerActions()
+	_ = LoadAndConfigure(kbgp)
+}
+
+// Loads the user bindings from storage and applies them over the
+// current ones. If nothing was saved yet, nothing changes.
+func LoadBindings(kbgp *input.KBGP) error {
+	data, err := storage.Load(BindingsFileName)
+	if errors.Is(err, fs.ErrNotExist) { return nil }
+	if err != nil { return err }
+	return DecodeBindings(kbgp, data)
+}
+
+func SaveBindings(kbgp *input.KBGP) error {
+	data, err := EncodeBindings(kbgp)
+	if err != nil { return err }
+	return storage.Save(BindingsFileName, data)
+}
+
+// Encodes all the rebindable actions as text, with one line per
+// action and device: "<kb|gp> <action id> <trigger>". See the
+// input package for the trigger format.
+func EncodeBindings(kbgp *input.KBGP) ([]byte, error) {
+	kbConfig, gpConfig := kbgp.Keyboard().Config(), kbgp.Gamepad().Config()
+	var buffer bytes.Buffer
+	buffer.WriteString(bindingsHeader + "\n")
+	for _, binding := range Bindings {
+		kbStr, err := input.EncodeKeyboardTrigger(kbConfig.GetMapping(binding.Action))
+		if err != nil { return nil, err }
+		gpStr, err := input.EncodeGamepadTrigger(gpConfig.GetMapping(binding.Action))
+		if err != nil { return nil, err }
+		fmt.Fprintf(&buffer, "kb %s %s\ngp %s %s\n", binding.ID, kbStr, binding.ID, gpStr)
+	}
+	return buffer.Bytes(), nil
+}
+
+// Applies the given bindings. Unknown actions and invalid triggers
+// are skipped, keeping the current bindings for them. Unassigned
+// triggers are applied as missing mappings, except for essential
+// bindings, which are never left unassigned.
+func DecodeBindings(kbgp *input.KBGP, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() || scanner.Text() != bindingsHeader { return ErrInvalidBindings }
+
+	kbConfig, gpConfig := kbgp.Keyboard().Config(), kbgp.Gamepad().Config()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 { continue }
+		action, found := findActionByID(fields[1])
+		if !found { continue }
+		essential := FindBinding(action).Essential
+		switch fields[0] {
+		case "kb":
+			trigger, err := input.ParseKeyboardTrigger(fields[2])
+			if err != nil { continue }
+			if _, unassigned := trigger.(input.UnassignedKey); unassigned {
+				if essential { continue }
+				trigger = nil
+			}
+			kbConfig.MapTriggerAction(action, trigger)
+		case "gp":
+			trigger, err := input.ParseGamepadTrigger(fields[2])
+			if err != nil { continue }
+			if _, unassigned := trigger.(input.UnassignedButton); unassigned {
+				if essential { continue }
+				trigger = nil
+			}
+			gpConfig.MapTriggerAction(action, trigger)
+		}
+	}
+	if scanner.Err() != nil { return ErrInvalidBindings }
+	return nil
+}
+
+func findActionByID(id string) (input.TriggerAction, bool) {
+	for _, binding := range Bindings {
+		if binding.ID == id { return binding.Action, true }
+	}
+	return 0, false
+}
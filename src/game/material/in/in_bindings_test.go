@@ -0,0 +1,76 @@
+package in
+
+import "bytes"
+import "testing"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+import "github.com/tinne26/luckyfeet/src/lib/input"
+
+func TestDefaultBindingsNoConflicts(t *testing.T) {
+	kbgp := input.NewKBGP()
+	err := LoadAndConfigure(kbgp)
+	if err != nil { t.Fatal(err) }
+	for _, binding := range Bindings {
+		kbNames, gpNames := Conflicts(kbgp, binding.Action)
+		if len(kbNames) > 0 || len(gpNames) > 0 {
+			t.Fatalf("%s conflicts: keyboard %v, gamepad %v", binding.Name, kbNames, gpNames)
+		}
+	}
+}
+
+func TestKeyTriggers(t *testing.T) {
+	kbgp := input.NewKBGP()
+	err := LoadAndConfigure(kbgp)
+	if err != nil { t.Fatal(err) }
+	if !KeyTriggers(kbgp, ActionBack, ebiten.KeyEscape) { t.Fatal("expected ESCAPE to trigger BACK") }
+	if KeyTriggers(kbgp, ActionBack, ebiten.KeySpace) { t.Fatal("unexpected SPACE triggering BACK") }
+	if !KeyTriggers(kbgp, ActionMenu, ebiten.KeyTab) { t.Fatal("expected TAB to trigger MENU") }
+	if KeyTriggers(kbgp, ActionNextTile, ebiten.KeyD) { t.Fatal("unexpected lone D triggering NEXT BLOCK") }
+	if !ButtonTriggers(kbgp, ActionBack, input.GamepadButtonRight) { t.Fatal("expected RIGHT BUTTON to trigger BACK") }
+	if ButtonTriggers(kbgp, ActionBack, input.GamepadButtonBottom) { t.Fatal("unexpected BOTTOM BUTTON triggering BACK") }
+}
+
+func TestBindingsEncoding(t *testing.T) {
+	kbgp := input.NewKBGP()
+	err := LoadAndConfigure(kbgp)
+	if err != nil { t.Fatal(err) }
+	kbConfig, gpConfig := kbgp.Keyboard().Config(), kbgp.Gamepad().Config()
+	var breakerCombo input.MultiKey
+	breakerCombo.AddNormalKey(ebiten.KeyQ)
+	breakerCombo.AddBreakerKey(ebiten.KeyShiftLeft)
+	kbConfig.MapTriggerAction(ActionJump, input.NewKeyList(ebiten.KeyK, ebiten.KeyL))
+	kbConfig.MapTriggerAction(ActionDash, breakerCombo)
+	kbConfig.DeleteTriggerAction(ActionUseCarrot)
+	gpConfig.MapTriggerAction(ActionJump, input.NewButtonList(input.GamepadButtonBottom, input.GamepadButtonLeft))
+	gpConfig.MapTriggerAction(ActionDash, input.NewMultiButton(input.GamepadShoulderLeft, input.GamepadButtonRight))
+
+	data, err := EncodeBindings(kbgp)
+	if err != nil { t.Fatal(err) }
+
+	decoded := input.NewKBGP()
+	err = LoadAndConfigure(decoded)
+	if err != nil { t.Fatal(err) }
+	err = DecodeBindings(decoded, data)
+	if err != nil { t.Fatal(err) }
+	if decoded.Keyboard().Config().GetMapping(ActionUseCarrot) != nil {
+		t.Fatal("expected USE CARROT to stay unassigned")
+	}
+	reencoded, err := EncodeBindings(decoded)
+	if err != nil { t.Fatal(err) }
+	if !bytes.Equal(data, reencoded) {
+		t.Fatalf("bindings changed after decoding:\n%s\nvs\n%s", data, reencoded)
+	}
+
+	// essential bindings can't be unassigned through the file
+	unassigned, err := input.EncodeKeyboardTrigger(nil)
+	if err != nil { t.Fatal(err) }
+	err = DecodeBindings(decoded, []byte(bindingsHeader + "\nkb back " + unassigned + "\ngp back " + unassigned + "\n"))
+	if err != nil { t.Fatal(err) }
+	if decoded.Keyboard().Config().GetMapping(ActionBack) == nil || decoded.Gamepad().Config().GetMapping(ActionBack) == nil {
+		t.Fatal("expected BACK to stay assigned")
+	}
+
+	err = DecodeBindings(decoded, []byte("luckyfeet settings v1\n"))
+	if err != ErrInvalidBindings { t.Fatalf("expected ErrInvalidBindings, got %v", err) }
+}
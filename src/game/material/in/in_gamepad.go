@@ -0,0 +1,103 @@
+package in
+
+import "fmt"
+import "sort"
+import "bytes"
+import "errors"
+import "io/fs"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+import "github.com/tinne26/luckyfeet/src/lib/input"
+import "github.com/tinne26/luckyfeet/src/game/storage"
+
+// Directory for gamepad layouts bundled with the game, in the
+// .dat format from input.GamepadLayout.Export.
+const GamepadLayoutsPath = "assets/gamepads/"
+
+// Storage name for the gamepad layouts set up by the user. The
+// file is just a sequence of .dat layouts.
+const GamepadLayoutsFileName = "gamepads.dat"
+
+// Loads the bundled gamepad layouts and then the user ones, which
+// take priority, and sets them on the gamepad. Layouts loaded
+// before an error are still set.
+func LoadAndConfigureGamepad(gamepad *input.Gamepad, filesys fs.FS) error {
+	layouts := make(map[input.GamepadGUID]input.GamepadLayout)
+	defer gamepad.Config().SetLayouts(layouts)
+
+	paths, err := fs.Glob(filesys, GamepadLayoutsPath + "*.dat")
+	if err != nil { return err }
+	for _, path := range paths {
+		data, err := fs.ReadFile(filesys, path)
+		if err != nil { return err }
+		err = decodeGamepadLayouts(data, layouts)
+		if err != nil { return fmt.Errorf("%s: %w", path, err) }
+	}
+
+	data, err := storage.Load(GamepadLayoutsFileName)
+	if errors.Is(err, fs.ErrNotExist) { return nil }
+	if err != nil { return err }
+	return decodeGamepadLayouts(data, layouts)
+}
+
+// Sets the layout for the given gamepad and saves it with the
+// other user layouts. Bundled layouts aren't saved, so they can
+// still be updated. The layout is set even if saving fails.
+func SaveGamepadLayout(gamepad *input.Gamepad, guid input.GamepadGUID, layout input.GamepadLayout) error {
+	layouts := gamepad.Config().GetLayouts()
+	if layouts == nil { layouts = make(map[input.GamepadGUID]input.GamepadLayout) }
+	layouts[guid] = layout
+	gamepad.Config().SetLayouts(layouts)
+
+	userLayouts := make(map[input.GamepadGUID]input.GamepadLayout)
+	data, err := storage.Load(GamepadLayoutsFileName)
+	if err == nil {
+		_ = decodeGamepadLayouts(data, userLayouts) // damaged data is replaced
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	userLayouts[guid] = layout
+	data, err = encodeGamepadLayouts(userLayouts)
+	if err != nil { return err }
+	return storage.Save(GamepadLayoutsFileName, data)
+}
+
+// Returns whether the active gamepad was just connected and can't
+// be used until set up: it has neither a known layout nor a
+// standard mapping to fall back to.
+func NeedsGamepadSetup(gamepad *input.Gamepad) bool {
+	status := gamepad.Status()
+	if !status.IsJustConnected() || status.IsConfigured() { return false }
+	id, found := gamepad.ActiveID()
+	return found && !ebiten.IsStandardGamepadLayoutAvailable(id)
+}
+
+func encodeGamepadLayouts(layouts map[input.GamepadGUID]input.GamepadLayout) ([]byte, error) {
+	guids := make([]input.GamepadGUID, 0, len(layouts))
+	for guid, _ := range layouts { guids = append(guids, guid) }
+	sort.Slice(guids, func(i, j int) bool {
+		return guids[i].ToString() < guids[j].ToString()
+	})
+
+	var buffer bytes.Buffer
+	for _, guid := range guids {
+		layout := layouts[guid]
+		err := layout.Export(&buffer, guid)
+		if err != nil { return nil, err }
+	}
+	return buffer.Bytes(), nil
+}
+
+func decodeGamepadLayouts(data []byte, layouts map[input.GamepadGUID]input.GamepadLayout) error {
+	if len(data) % input.GamepadLayoutDatSize != 0 {
+		return fmt.Errorf("gamepad layouts data must be a multiple of %d bytes", input.GamepadLayoutDatSize)
+	}
+	for len(data) > 0 {
+		guid, layout, err := input.ParseGamepadLayout(data[ : input.GamepadLayoutDatSize])
+		if err != nil { return err }
+		layouts[guid] = layout
+		data = data[input.GamepadLayoutDatSize : ]
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package in
+
+import "testing"
+
+import "github.com/tinne26/luckyfeet/src/lib/input"
+
+func TestGamepadLayoutsEncoding(t *testing.T) {
+	guidA, err := input.StringToGamepadGUID("030000005e0400008e02000014010000")
+	if err != nil { t.Fatal(err) }
+	guidB, err := input.StringToGamepadGUID("0300000079000000060000001101000a")
+	if err != nil { t.Fatal(err) }
+	var custom input.GamepadLayout
+	custom[input.GamepadUp] = input.NewGamepadBinaryButtonLayoutInput(9)
+	custom[input.GamepadLeftStickHorzAxis] = input.NewGamepadAxisLayoutInput(0)
+	custom[input.GamepadTriggerLeft] = input.NewGamepadAnalogButtonLayoutInput(4)
+	layouts := map[input.GamepadGUID]input.GamepadLayout{
+		guidA: input.StandardGamepadLayout(),
+		guidB: custom,
+	}
+
+	data, err := encodeGamepadLayouts(layouts)
+	if err != nil { t.Fatal(err) }
+	if len(data) != 2*input.GamepadLayoutDatSize {
+		t.Fatalf("expected %d bytes, got %d", 2*input.GamepadLayoutDatSize, len(data))
+	}
+	decoded := make(map[input.GamepadGUID]input.GamepadLayout)
+	err = decodeGamepadLayouts(data, decoded)
+	if err != nil { t.Fatal(err) }
+	if len(decoded) != 2 || decoded[guidA] != layouts[guidA] || decoded[guidB] != custom {
+		t.Fatalf("layouts changed after decoding")
+	}
+
+	err = decodeGamepadLayouts(data[ : len(data) - 1], decoded)
+	if err == nil { t.Fatal("expected error on truncated data") }
+}
@@ -1,7 +1,5 @@
 package in
 
-import "io/fs"
-
 import "github.com/hajimehoshi/ebiten/v2"
 
 import "github.com/tinne26/luckyfeet/src/lib/input"
@@ -14,6 +12,7 @@ func LoadAndConfigure(kbgp *input.KBGP) error {
 	kbConfig.MapTriggerActionToKey(ActionUseCarrot, ebiten.KeyO)
 	kbConfig.MapTriggerActionToKey(ActionPrevCarrot, ebiten.KeyI)
 	kbConfig.MapTriggerActionToKey(ActionNextCarrot, ebiten.KeyP)
+	kbConfig.MapTriggerActionToKey(ActionDash, ebiten.KeyL)
 	kbConfig.MapTriggerActionToKey(ActionFullscreen, ebiten.KeyF)
 	kbConfig.MapTriggerActionToKey(ActionToggleFPS, ebiten.KeyDigit1)
 	kbConfig.MapTriggerActionToKey(ActionModKey, ebiten.KeyAltLeft)
@@ -46,6 +45,7 @@ func LoadAndConfigure(kbgp *input.KBGP) error {
 	gpConfig.MapTriggerActionToButton(ActionUseCarrot, input.GamepadButtonLeft)
 	gpConfig.MapTriggerActionToButton(ActionPrevCarrot, input.GamepadShoulderLeft)
 	gpConfig.MapTriggerActionToButton(ActionNextCarrot, input.GamepadShoulderRight)
+	gpConfig.MapTriggerActionToButton(ActionDash, input.GamepadButtonTop)
 
 	var multiPrevTile input.MultiButton
 	multiPrevTile.AddBreaker(input.GamepadButtonLeft)
@@ -69,8 +69,3 @@ func LoadAndConfigure(kbgp *input.KBGP) error {
 	
 	return nil
 }
-
-func LoadAndConfigureGamepad(gamepad *input.Gamepad, filesys fs.FS) error {
-	
-	return nil
-}
@@ -0,0 +1,166 @@
+package in
+
+import "cmp"
+import "slices"
+import "strings"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+import "github.com/tinne26/luckyfeet/src/lib/input"
+import "github.com/tinne26/luckyfeet/src/lib/text"
+
+// Name used for conflicts with the movement keys and buttons.
+const MovementName = "MOVEMENT"
+
+// Returns the names of the actions that share a key combination
+// or a button combination with the given action. Actions only
+// conflict if they can be used at the same time.
+func Conflicts(kbgp *input.KBGP, action input.TriggerAction) (kbNames, gpNames []string) {
+	binding := FindBinding(action)
+	if binding == nil { return nil, nil }
+	kbConfig, gpConfig := kbgp.Keyboard().Config(), kbgp.Gamepad().Config()
+	kbCombos := keyCombos(kbConfig.GetMapping(action))
+	gpCombos := buttonCombos(gpConfig.GetMapping(action))
+
+	// movement is used everywhere except menus
+	if binding.Group != GroupMenu {
+		dirs := kbConfig.GetDirTriggers()
+		for _, trigger := range []input.KeyboardTrigger{ dirs.Up, dirs.Down, dirs.Right, dirs.Left } {
+			if combosOverlap(kbCombos, keyCombos(trigger)) { kbNames = append(kbNames, MovementName) ; break }
+		}
+		dirButtons := gpConfig.GetDirButtons()
+		for _, button := range []input.GamepadStandardInput{ dirButtons.Up, dirButtons.Down, dirButtons.Right, dirButtons.Left } {
+			if combosOverlap(gpCombos, buttonCombos(input.SingleButton(button))) { gpNames = append(gpNames, MovementName) ; break }
+		}
+	}
+
+	for _, other := range Bindings {
+		if other.Action == action { continue }
+		if binding.Group != other.Group && binding.Group != GroupGlobal && other.Group != GroupGlobal { continue }
+		if combosOverlap(kbCombos, keyCombos(kbConfig.GetMapping(other.Action))) {
+			kbNames = append(kbNames, other.Name)
+		}
+		if combosOverlap(gpCombos, buttonCombos(gpConfig.GetMapping(other.Action))) {
+			gpNames = append(gpNames, other.Name)
+		}
+	}
+	return kbNames, gpNames
+}
+
+// Returns whether pressing only the given key triggers the action.
+func KeyTriggers(kbgp *input.KBGP, action input.TriggerAction, key ebiten.Key) bool {
+	trigger := kbgp.Keyboard().Config().GetMapping(action)
+	return combosOverlap([][]ebiten.Key{ { key } }, keyCombos(trigger))
+}
+
+// Returns whether pressing only the given button triggers the action.
+func ButtonTriggers(kbgp *input.KBGP, action input.TriggerAction, button input.GamepadStandardInput) bool {
+	trigger := kbgp.Gamepad().Config().GetMapping(action)
+	return combosOverlap([][]input.GamepadStandardInput{ { button } }, buttonCombos(trigger))
+}
+
+// Returns the sorted keys that must be pressed together for
+// each alternative of the trigger. Breakers are ignored.
+func keyCombos(trigger input.KeyboardTrigger) [][]ebiten.Key {
+	if trigger == nil { return nil }
+	var builder comboBuilder[ebiten.Key]
+	for _, in := range trigger.Inputs() { builder.add(in.Key, in.Mode) }
+	return builder.combos()
+}
+
+func buttonCombos(trigger input.GamepadTrigger) [][]input.GamepadStandardInput {
+	if trigger == nil { return nil }
+	var builder comboBuilder[input.GamepadStandardInput]
+	for _, in := range trigger.Inputs() { builder.add(in.Button, in.Mode) }
+	return builder.combos()
+}
+
+type comboBuilder[T cmp.Ordered] struct {
+	alts [][]T
+	multi []T
+}
+
+func (self *comboBuilder[T]) add(value T, mode input.InputMode) {
+	switch mode {
+	case input.InputModeNormal: self.multi = append(self.multi, value)
+	case input.InputModeOr: self.alts = append(self.alts, []T{ value })
+	}
+}
+
+func (self *comboBuilder[T]) combos() [][]T {
+	if len(self.multi) == 0 { return self.alts }
+	multi := slices.Clone(self.multi)
+	slices.Sort(multi)
+	return append(self.alts, multi)
+}
+
+func combosOverlap[T cmp.Ordered](a, b [][]T) bool {
+	for _, comboA := range a {
+		for _, comboB := range b {
+			if slices.Equal(comboA, comboB) { return true }
+		}
+	}
+	return false
+}
+
+// Returns the trigger as text for the rebind menu, with "-" for
+// unassigned triggers.
+func KeyboardTriggerName(trigger input.KeyboardTrigger) string {
+	if trigger == nil { return "-" }
+	var alts, combo, breakers []string
+	for _, in := range trigger.Inputs() {
+		name := strings.ToUpper(in.Key.String())
+		switch in.Mode {
+		case input.InputModeNormal : combo = append(combo, name)
+		case input.InputModeOr     : alts = append(alts, name)
+		case input.InputModeBreaker: breakers = append(breakers, name)
+		}
+	}
+	return triggerName(alts, combo, breakers)
+}
+
+func GamepadTriggerName(trigger input.GamepadTrigger) string {
+	if trigger == nil { return "-" }
+	var alts, combo, breakers []string
+	for _, in := range trigger.Inputs() {
+		name := ButtonName(in.Button)
+		switch in.Mode {
+		case input.InputModeNormal : combo = append(combo, name)
+		case input.InputModeOr     : alts = append(alts, name)
+		case input.InputModeBreaker: breakers = append(breakers, name)
+		}
+	}
+	return triggerName(alts, combo, breakers)
+}
+
+func triggerName(alts, combo, breakers []string) string {
+	if len(combo) > 0 { alts = append(alts, strings.Join(combo, " + ")) }
+	if len(alts) == 0 { return "-" }
+	name := strings.Join(alts, " / ")
+	if len(breakers) > 0 { name += " (NO " + strings.Join(breakers, ", ") + ")" }
+	return name
+}
+
+func ButtonName(button input.GamepadStandardInput) string {
+	switch button {
+	case input.GamepadUp    : return "D-PAD UP"
+	case input.GamepadRight : return "D-PAD RIGHT"
+	case input.GamepadLeft  : return "D-PAD LEFT"
+	case input.GamepadDown  : return "D-PAD DOWN"
+	case input.GamepadButtonTop   : return string(text.GpBtTop)
+	case input.GamepadButtonRight : return string(text.GpBtRight)
+	case input.GamepadButtonLeft  : return string(text.GpBtLeft)
+	case input.GamepadButtonBottom: return string(text.GpBtBottom)
+	case input.GamepadShoulderLeft : return string(text.GpShoulderL)
+	case input.GamepadShoulderRight: return string(text.GpShoulderR)
+	case input.GamepadTriggerLeft  : return string(text.GpTriggL)
+	case input.GamepadTriggerRight : return string(text.GpTriggR)
+	case input.GamepadLeftStickButton : return "L STICK"
+	case input.GamepadRightStickButton: return "R STICK"
+	case input.GamepadStart : return "START"
+	case input.GamepadSelect: return "SELECT"
+	case input.GamepadMeta  : return "META"
+	default:
+		return "?"
+	}
+}
@@ -1,6 +1,8 @@
 package level
 
 import "strconv"
+import "strings"
+import "hash/fnv"
 
 type Key uint8
 
@@ -8,11 +10,42 @@ const (
 	Guidance Key = iota
 	FirstRace
 	Bunny
+	keyCountSentinel
 )
 
 // TODO: there's a bug where if I edit a level and then click play, the same level I was editing will pop up. bad.
 // must also fix bad cases of locking, and push towards most reasonable side in case of floor touch. and visual
-// indicator for current layer. and not getting trapped turning around on an edge.
+// indicator for current layer.
+
+// Level packs are identified by the hash of their data, which
+// is stored in replays to make sure they are played back on the
+// same maps. Surrounding whitespace is ignored.
+func PackHash(data string) uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(strings.TrimSpace(data)))
+	return hash.Sum64()
+}
+
+// Returns the key of the built-in level with the given pack hash.
+func FindPack(hash uint64) (Key, bool) {
+	for key := Key(0); key < keyCountSentinel; key++ {
+		if PackHash(GetData(key)) == hash { return key, true }
+	}
+	return 0, false
+}
+
+// Returns the par time of the given built-in level, in ticks.
+// Clearing under par is meant to need a decent run, but nothing
+// close to a perfect one.
+func ParTicks(key Key) int {
+	switch key {
+	case Guidance : return 60*120
+	case FirstRace: return 150*120
+	case Bunny    : return 45*120
+	default:
+		panic("unexpected level key " + strconv.Itoa(int(key)))
+	}
+}
 
 // Returns the encoded data of the requested level, as a string.
 func GetData(key Key) string {
@@ -0,0 +1,15 @@
+package level
+
+import "testing"
+
+func TestFindPack(t *testing.T) {
+	for key := Key(0); key < keyCountSentinel; key++ {
+		found, ok := FindPack(PackHash(GetData(key)))
+		if !ok || found != key { t.Fatalf("expected key %d, got %d (ok = %t)", key, found, ok) }
+	}
+	if PackHash(" " + GetData(Bunny) + "\n") != PackHash(GetData(Bunny)) {
+		t.Fatal("expected surrounding whitespace to be ignored")
+	}
+	_, ok := FindPack(PackHash("not a level"))
+	if ok { t.Fatal("unexpected pack found") }
+}
@@ -8,6 +8,7 @@ const (
 	Play
 	BriefBlackout
 	WinScreen
+	GamepadSetup
 	// ...
 )
 
@@ -8,6 +8,7 @@ import "github.com/tinne26/luckyfeet/src/game/scene/editor"
 import "github.com/tinne26/luckyfeet/src/game/scene/play"
 import "github.com/tinne26/luckyfeet/src/game/scene/briefblackout"
 import "github.com/tinne26/luckyfeet/src/game/scene/winscreen"
+import "github.com/tinne26/luckyfeet/src/game/scene/gamepadsetup"
 
 func NewSceneManager() *scene.Manager[*context.Context] {
 	registry := scene.NewRegistry[*context.Context]()
@@ -18,6 +19,7 @@ func NewSceneManager() *scene.Manager[*context.Context] {
 	registry.Register(play.RequestHook, play.SceneKey())
 	registry.Register(briefblackout.RequestHook, briefblackout.SceneKey())
 	registry.Register(winscreen.RequestHook, winscreen.SceneKey())
+	registry.Register(gamepadsetup.RequestHook, gamepadsetup.SceneKey())
 
 	return scene.NewManager(registry)
 }
@@ -0,0 +1,5 @@
+package version
+
+// Game version, stored in replays and other shared data. Must be
+// updated on releases, as physics changes can break old replays.
+const Game = "v0.0.4-dev"
@@ -0,0 +1,50 @@
+package player
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+
+type EventKind uint8
+const (
+	EventJumped EventKind = iota
+	EventSlipJumped // jump started while slipping off an edge
+	EventTicTacked
+	EventWallJumped
+	EventDashed
+	EventLanded
+	EventStartedFalling
+	EventSlipped
+	EventRespawned
+	EventLayerChanged
+	EventStep // running animation footsteps
+	EventLowStep
+)
+
+type Event struct {
+	Kind EventKind
+	X, Y int // top-left corner of the player collision rect
+	Layer int // player layer after the event
+}
+
+// Subscribers are notified of player events in the same order
+// they happen during the player update. See [Player.Subscribe]().
+type Subscriber interface {
+	HandlePlayerEvent(ctx *context.Context, event Event)
+}
+
+func (self EventKind) String() string {
+	switch self {
+	case EventJumped: return "Jumped"
+	case EventSlipJumped: return "SlipJumped"
+	case EventTicTacked: return "TicTacked"
+	case EventWallJumped: return "WallJumped"
+	case EventDashed: return "Dashed"
+	case EventLanded: return "Landed"
+	case EventStartedFalling: return "StartedFalling"
+	case EventSlipped: return "Slipped"
+	case EventRespawned: return "Respawned"
+	case EventLayerChanged: return "LayerChanged"
+	case EventStep: return "Step"
+	case EventLowStep: return "LowStep"
+	default:
+		return "Unknown Event"
+	}
+}
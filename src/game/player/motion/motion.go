@@ -2,10 +2,8 @@ package motion
 
 import "github.com/hajimehoshi/ebiten/v2"
 
-import "github.com/tinne26/luckyfeet/src/lib/audio"
-
-import "github.com/tinne26/luckyfeet/src/game/material/au"
-
+// Sound cues attached to animation frames. Animations don't play
+// them, they only report them when the frame is reached.
 type SfxKey uint8
 const (
 	SfxNone SfxKey = iota
@@ -53,59 +51,52 @@ func (self *Animation) GetCurrentFrame() *ebiten.Image {
 	return self.frames[self.frameIndex]
 }
 
+func (self *Animation) FrameIndex() uint8 {
+	return self.frameIndex
+}
+
+// Frame indices out of range are clamped.
+func (self *Animation) GetFrame(index uint8) *ebiten.Image {
+	return self.frames[min(int(index), len(self.frames) - 1)]
+}
+
 func (self *Animation) InPreLoopPhase() bool {
 	return self.frameIndex < self.loopIndex
 }
 
-func (self *Animation) SkipIntro(soundscape *audio.Soundscape) {
+func (self *Animation) SkipIntro() SfxKey {
 	self.frameIndex = self.loopIndex
 	self.frameDurationLeft = self.frameDurations[self.loopIndex]
-	self.playSfx(soundscape)
+	return self.sfxs[self.frameIndex]
 }
 
-func (self *Animation) Rewind(soundscape *audio.Soundscape) {
+func (self *Animation) Rewind() SfxKey {
 	self.frameIndex = 0
 	self.frameDurationLeft = self.frameDurations[0]
-	self.playSfx(soundscape)
+	return self.sfxs[self.frameIndex]
 }
 
-func (self *Animation) RewindToLoop(soundscape *audio.Soundscape) {
+func (self *Animation) RewindToLoop() SfxKey {
 	self.frameIndex = self.loopIndex
 	self.frameDurationLeft = self.frameDurations[self.loopIndex]
-	self.playSfx(soundscape)
+	return self.sfxs[self.frameIndex]
 }
 
-func (self *Animation) Update(soundscape *audio.Soundscape) {
+// Returns the sfx key of the new frame if the frame changed,
+// or SfxNone otherwise.
+func (self *Animation) Update() SfxKey {
 	self.frameDurationLeft -= 1
-	if self.frameDurationLeft == 0 {
-		if self.frameIndex == uint8(len(self.frames) - 1) {
-			self.frameIndex = self.loopIndex
-		} else {
-			self.frameIndex += 1
-		}
-		self.playSfx(soundscape)
-		self.frameDurationLeft = self.frameDurations[self.frameIndex]
+	if self.frameDurationLeft != 0 { return SfxNone }
+
+	if self.frameIndex == uint8(len(self.frames) - 1) {
+		self.frameIndex = self.loopIndex
+	} else {
+		self.frameIndex += 1
 	}
+	self.frameDurationLeft = self.frameDurations[self.frameIndex]
+	return self.sfxs[self.frameIndex]
 }
 
 func (self *Animation) SetLoopStart(index uint8) {
 	self.loopIndex = index
 }
-
-func (self *Animation) playSfx(soundscape *audio.Soundscape) {
-	sfxKey := self.sfxs[self.frameIndex]
-	switch sfxKey {
-	case SfxNone:
-		// nothing
-	case SfxStep:
-	 	soundscape.PlaySFX(au.SfxStep)
-	case SfxLowStep:
-		soundscape.PlaySFX(au.SfxLowStep)
-	// case SfxJump:
-	// 	soundscape.PlaySFX(au.SfxJump)
-	// case SfxDeath:
-	// 	soundscape.PlaySFX(au.SfxDeath)
-	default:
-		panic(sfxKey)
-	}
-}
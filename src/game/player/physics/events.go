@@ -0,0 +1,33 @@
+package physics
+
+type EventKind uint8
+const (
+	EvStateChanged EventKind = iota // always emitted, even if the state is the same
+	EvJumped // emitted before the related state change
+	EvSlipJumped // jump started while slipping, before the related state change
+	EvTicTacked // emitted before the related state change
+	EvLanded // emitted after the related state change
+	EvSlipped
+	EvWallJumped // emitted before the related state change
+	EvDashed // emitted before the related state change
+)
+
+type Event struct {
+	Kind EventKind
+	State State // only relevant for EvStateChanged
+}
+
+func (self EventKind) String() string {
+	switch self {
+	case EvStateChanged: return "StateChanged"
+	case EvJumped: return "Jumped"
+	case EvSlipJumped: return "SlipJumped"
+	case EvTicTacked: return "TicTacked"
+	case EvLanded: return "Landed"
+	case EvSlipped: return "Slipped"
+	case EvWallJumped: return "WallJumped"
+	case EvDashed: return "Dashed"
+	default:
+		return "Unknown Event"
+	}
+}
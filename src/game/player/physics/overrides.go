@@ -0,0 +1,125 @@
+package physics
+
+import "math"
+
+// Identifies a [Profile] field, so maps can override them one by one.
+// The order must be preserved, as it's used for serialization.
+type ProfileField uint8
+const (
+	FieldRunSpeed ProfileField = iota
+	FieldJumpInitialSpeed
+	FieldDefaultGravity
+	FieldExtraGravity
+	FieldMaxFallSpeed
+	FieldAirExtraHorzSpeed
+	FieldTicTacAirHorzSpeedMult
+	FieldCoyoteTicks
+	FieldJumpBufferTicks
+	FieldWallSlideSpeed
+	FieldWallJumpPushSpeed
+	FieldWallJumpPushTicks
+	FieldDashSpeed
+	FieldDashTicks
+	ProfileFieldCountSentinel
+)
+
+func (self ProfileField) IsTicks() bool {
+	switch self {
+	case FieldCoyoteTicks, FieldJumpBufferTicks, FieldWallJumpPushTicks, FieldDashTicks:
+		return true
+	default:
+		return false
+	}
+}
+
+// Tick counts are returned as float64 too.
+func (self *Profile) Get(field ProfileField) float64 {
+	switch field {
+	case FieldRunSpeed              : return self.RunSpeed
+	case FieldJumpInitialSpeed      : return self.JumpInitialSpeed
+	case FieldDefaultGravity        : return self.DefaultGravity
+	case FieldExtraGravity          : return self.ExtraGravity
+	case FieldMaxFallSpeed          : return self.MaxFallSpeed
+	case FieldAirExtraHorzSpeed     : return self.AirExtraHorzSpeed
+	case FieldTicTacAirHorzSpeedMult: return self.TicTacAirHorzSpeedMult
+	case FieldCoyoteTicks           : return float64(self.CoyoteTicks)
+	case FieldJumpBufferTicks       : return float64(self.JumpBufferTicks)
+	case FieldWallSlideSpeed        : return self.WallSlideSpeed
+	case FieldWallJumpPushSpeed     : return self.WallJumpPushSpeed
+	case FieldWallJumpPushTicks     : return float64(self.WallJumpPushTicks)
+	case FieldDashSpeed             : return self.DashSpeed
+	case FieldDashTicks             : return float64(self.DashTicks)
+	default:
+		panic("invalid profile field")
+	}
+}
+
+// Values for tick fields are truncated.
+func (self *Profile) Set(field ProfileField, value float64) {
+	switch field {
+	case FieldRunSpeed              : self.RunSpeed = value
+	case FieldJumpInitialSpeed      : self.JumpInitialSpeed = value
+	case FieldDefaultGravity        : self.DefaultGravity = value
+	case FieldExtraGravity          : self.ExtraGravity = value
+	case FieldMaxFallSpeed          : self.MaxFallSpeed = value
+	case FieldAirExtraHorzSpeed     : self.AirExtraHorzSpeed = value
+	case FieldTicTacAirHorzSpeedMult: self.TicTacAirHorzSpeedMult = value
+	case FieldCoyoteTicks           : self.CoyoteTicks = int(value)
+	case FieldJumpBufferTicks       : self.JumpBufferTicks = int(value)
+	case FieldWallSlideSpeed        : self.WallSlideSpeed = value
+	case FieldWallJumpPushSpeed     : self.WallJumpPushSpeed = value
+	case FieldWallJumpPushTicks     : self.WallJumpPushTicks = int(value)
+	case FieldDashSpeed             : self.DashSpeed = value
+	case FieldDashTicks             : self.DashTicks = int(value)
+	default:
+		panic("invalid profile field")
+	}
+}
+
+// Optional per-field values applied on top of a preset profile,
+// so maps can store tuned physics. The zero value overrides
+// nothing. Comparable with ==.
+type Overrides struct {
+	mask uint16
+	values [ProfileFieldCountSentinel]float64
+}
+
+// Returns the overrides that turn the base profile into the
+// tuned one.
+func NewOverrides(base, tuned Profile) Overrides {
+	var overrides Overrides
+	for field := ProfileField(0); field < ProfileFieldCountSentinel; field++ {
+		value := tuned.Get(field)
+		if value != base.Get(field) { overrides.Set(field, value) }
+	}
+	return overrides
+}
+
+func (self *Overrides) IsZero() bool { return self.mask == 0 }
+
+func (self *Overrides) Get(field ProfileField) (float64, bool) {
+	if self.mask & (1 << field) == 0 { return 0, false }
+	return self.values[field], true
+}
+
+// Values must pass [IsValidOverride]. Tick values are truncated.
+func (self *Overrides) Set(field ProfileField, value float64) {
+	if field >= ProfileFieldCountSentinel { panic("invalid profile field") }
+	if !IsValidOverride(value) { panic("invalid profile value") }
+	if field.IsTicks() { value = math.Trunc(value) }
+	self.mask |= 1 << field
+	self.values[field] = value
+}
+
+// Reports whether the value is finite and not negative, like the
+// ones reachable through tuning.
+func IsValidOverride(value float64) bool {
+	return value >= 0 && !math.IsInf(value, 0) // NaN fails the first check
+}
+
+func (self *Overrides) Apply(profile *Profile) {
+	for field := ProfileField(0); field < ProfileFieldCountSentinel; field++ {
+		value, found := self.Get(field)
+		if found { profile.Set(field, value) }
+	}
+}
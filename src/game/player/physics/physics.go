@@ -0,0 +1,141 @@
+package physics
+
+import "image"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+
+// Horizontal direction, kept separate from the input package so
+// physics can be stepped without ebiten.
+type Dir uint8
+const (
+	DirNone Dir = iota
+	DirRight
+	DirLeft
+)
+
+// Input relevant to the physics for a single tick.
+type Frame struct {
+	Horz Dir
+	Deflection float64 // analog stick amount in (0, 1), zero for digital input
+	JumpTrigger bool // jump pressed this tick
+	JumpPressed bool // jump held down
+	DashTrigger bool // dash pressed this tick
+}
+
+// Horizontal speed multiplier for the frame. Digital input
+// always moves at full speed.
+func (self *Frame) HorzFactor() float64 {
+	if self.Deflection <= 0 || self.Deflection >= 1.0 { return 1.0 }
+	return self.Deflection
+}
+
+// Collision queries required by the physics. Layers follow the
+// tcsts.Layer* constants.
+type World interface {
+	Collides(rect image.Rectangle, layer int) bool
+	HasLandingFor(ox, fx, y int, layer int) bool
+	ZoneAt(x, y int) (uint8, bool) // zone tile ID at the given logical position
+	IsSlopeAt(x, y int, layer int) bool
+}
+
+// Full movement state of the player. Bodies are plain values:
+// same body, frame and world always lead to the same result.
+type Body struct {
+	State State
+	X, Y float64
+	Dir Dir // facing direction, never DirNone
+	Layer int // last active layer
+	Box Box
+	Profile Profile
+	Abilities Abilities
+
+	VertSpeed float64
+	JumpSpeedGainLeft float64
+	JumpingTicks int
+	JumpHoldStopTick int
+	DidTicTac bool
+	TicksInExtraGravity int
+	CoyoteTicksLeft int
+	JumpBufferLeft int // jump presses are kept for a few ticks
+	WallJumpTicksLeft int
+	DashTicksLeft int
+	DidDash bool // only one dash per airtime
+
+	ZoneWind float64 // horizontal speed added while airborne
+	ZoneGravityFactor float64
+}
+
+func NewBody() Body {
+	return Body{
+		State: StFalling,
+		Dir: DirRight,
+		Layer: tcsts.LayerMain,
+		Box: DefaultBox,
+		Profile: ProfileClassic,
+		JumpHoldStopTick: 9999,
+		DidTicTac: true,
+		ZoneGravityFactor: 1.0,
+	}
+}
+
+// Places the body idle at the given map start point. The facing
+// direction is preserved.
+func (self *Body) Respawn(startRow, startCol uint8, layer int) {
+	self.State = StIdle
+	self.X = float64(startCol)*20 + 2
+	self.Y = float64(startRow)*20 - float64(self.Box.Height) + 11
+	self.VertSpeed = 0
+	self.JumpSpeedGainLeft = 0
+	self.JumpingTicks = 0
+	self.DidTicTac = false
+	self.TicksInExtraGravity = 0
+	self.CoyoteTicksLeft = 0
+	self.JumpBufferLeft = 0
+	self.WallJumpTicksLeft = 0
+	self.DashTicksLeft = 0
+	self.DidDash = false
+	self.Layer = layer
+}
+
+// Advances the body by a single tick. Events are appended to the
+// given slice, which can be reused between calls.
+func Step(body Body, frame Frame, world World, events []Event) (Body, []Event) {
+	stepper := stepper{ Body: body, frame: frame, world: world, events: events }
+	stepper.update()
+	return stepper.Body, stepper.events
+}
+
+func (self *Body) XYi() (int, int) {
+	return int(self.X), int(self.Y)
+}
+
+func (self *Body) CollisionRect() image.Rectangle {
+	ix, iy := self.XYi()
+	return image.Rect(ix, iy, ix + self.Box.Width, iy + self.Box.Height)
+}
+
+func (self *Body) HasFallen() bool {
+	return self.Y > float64(360 + self.Box.Height + 16 + 120)
+}
+
+// Collision box of a character. Offsets are the position of the
+// box within the character sprite frames.
+type Box struct {
+	Width, Height int
+	XOffset, YOffset int
+}
+
+var DefaultBox = Box{ Width: 9, Height: 28, XOffset: 3, YOffset: 7 }
+
+const SlipSpeed = 0.3
+
+// Max pixels the player can be moved up or down to stay grounded
+// when running on slopes. Slopes are only checked at the center of
+// the player, so going from a slope to flat ground (or the other
+// way around) can require a few pixels at once.
+const SlopeSnapDistance = 6
+
+// Wind only pushes the player while airborne, while updrafts scale
+// down gravity. Zones are looked up at the center of the player.
+const WindZoneSpeed = 0.45
+const UpdraftGravityFactor = 0.3
@@ -0,0 +1,557 @@
+package physics
+
+import "image"
+import "testing"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+
+// simple world made of solid rects, landable on their top edge,
+// and 45 deg. ramps that follow the same rules as tile slopes
+type testWorld struct {
+	solids [tcsts.LayerCountSentinel][]image.Rectangle
+	ramps [tcsts.LayerCountSentinel][]testRamp
+}
+
+// square area with the surface going from the bottom left
+// to the top right corner, or the opposite if mirrored
+type testRamp struct {
+	Area image.Rectangle
+	Mirrored bool
+}
+
+func (self *testRamp) surfaceAt(x int) int {
+	if self.Mirrored { return self.Area.Min.Y + (x - self.Area.Min.X) }
+	return self.Area.Max.Y - 1 - (x - self.Area.Min.X)
+}
+
+func (self *testWorld) Collides(rect image.Rectangle, layer int) bool {
+	for _, solid := range self.solids[layer] {
+		if solid.Overlaps(rect) { return true }
+	}
+	footX := rect.Min.X + rect.Dx()/2
+	for _, ramp := range self.ramps[layer] {
+		if footX < ramp.Area.Min.X || footX >= ramp.Area.Max.X { continue }
+		if rect.Max.Y > ramp.surfaceAt(footX) && rect.Min.Y < ramp.Area.Max.Y { return true }
+	}
+	return false
+}
+
+func (self *testWorld) HasLandingFor(ox, fx, y int, layer int) bool {
+	for _, solid := range self.solids[layer] {
+		if solid.Min.Y == y && solid.Min.X <= fx && solid.Max.X >= ox { return true }
+	}
+	if ox != fx { return false }
+	for _, ramp := range self.ramps[layer] {
+		if ox < ramp.Area.Min.X || ox >= ramp.Area.Max.X { continue }
+		if ramp.surfaceAt(ox) == y { return true }
+	}
+	return false
+}
+
+func (self *testWorld) IsSlopeAt(x, y int, layer int) bool {
+	for _, ramp := range self.ramps[layer] {
+		if image.Pt(x, y).In(ramp.Area) { return true }
+	}
+	return false
+}
+
+func (self *testWorld) ZoneAt(x, y int) (uint8, bool) {
+	return tcsts.TileTypeMax, false
+}
+
+const testFloorY = 200
+
+func newTestWorld() *testWorld {
+	world := &testWorld{}
+	world.solids[tcsts.LayerMain] = append(world.solids[tcsts.LayerMain], image.Rect(0, testFloorY, 100, testFloorY + 20))
+	return world
+}
+
+func newIdleBody(x float64) Body {
+	body := NewBody()
+	body.State = StIdle
+	body.DidTicTac = false
+	body.X, body.Y = x, float64(testFloorY - DefaultBox.Height)
+	return body
+}
+
+func hasEvent(events []Event, kind EventKind) bool {
+	for _, event := range events {
+		if event.Kind == kind { return true }
+	}
+	return false
+}
+
+// Steps until the body is grounded again, returning the min y reached.
+func stepJump(t *testing.T, body Body, world World, holdTicks int) (Body, float64) {
+	var events []Event
+	minY := body.Y
+	body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+	for tick := 1; tick < 1000; tick++ {
+		frame := Frame{ JumpPressed: tick < holdTicks }
+		body, events = Step(body, frame, world, events[ : 0])
+		minY = min(minY, body.Y)
+		if hasEvent(events, EvLanded) { return body, minY }
+	}
+	t.Fatalf("jump didn't land after 1000 ticks (state %s)", body.State)
+	return body, minY
+}
+
+func TestJump(t *testing.T) {
+	world := newTestWorld()
+	body := newIdleBody(40)
+
+	var events []Event
+	body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events)
+	if len(events) != 2 || events[0].Kind != EvJumped || events[1].Kind != EvStateChanged || events[1].State != StJumpingHold {
+		t.Fatalf("unexpected jump events %v", events)
+	}
+	if body.State != StJumpingHold {
+		t.Fatalf("expected state %s, got %s", StJumpingHold, body.State)
+	}
+
+	body = newIdleBody(40)
+	landed, tapMinY := stepJump(t, body, world, 1)
+	if landed.State != StIdle || landed.Y != float64(testFloorY - DefaultBox.Height) {
+		t.Fatalf("expected idle landing at y = %d, got %s at y = %f", testFloorY - DefaultBox.Height, landed.State, landed.Y)
+	}
+	_, holdMinY := stepJump(t, body, world, 120)
+	if tapMinY >= landed.Y {
+		t.Fatalf("tap jump didn't go up")
+	}
+	if holdMinY >= tapMinY {
+		t.Fatalf("held jump apex (%f) expected to be higher than tap jump apex (%f)", holdMinY, tapMinY)
+	}
+}
+
+func TestTicTac(t *testing.T) {
+	world := newTestWorld()
+	world.solids[tcsts.LayerBack] = append(world.solids[tcsts.LayerBack], image.Rect(30, 0, 60, testFloorY))
+	body := newIdleBody(40)
+
+	var events []Event
+	body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+	for i := 0; i < 10; i++ {
+		body, events = Step(body, Frame{ JumpPressed: true }, world, events[ : 0])
+	}
+	body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+	if !hasEvent(events, EvTicTacked) {
+		t.Fatalf("expected tic-tac, got events %v", events)
+	}
+	if body.State != StTicTacHold || !body.DidTicTac || body.Layer != tcsts.LayerBack {
+		t.Fatalf("unexpected body after tic-tac: %+v", body)
+	}
+
+	// only one tic-tac per jump
+	body, events = Step(body, Frame{}, world, events[ : 0])
+	body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+	if hasEvent(events, EvTicTacked) {
+		t.Fatalf("unexpected second tic-tac")
+	}
+
+	// without a back wall, no tic-tac is possible
+	world.solids[tcsts.LayerBack] = nil
+	body = newIdleBody(40)
+	body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+	body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+	body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+	if hasEvent(events, EvTicTacked) || body.Layer != tcsts.LayerMain {
+		t.Fatalf("unexpected tic-tac without back wall")
+	}
+}
+
+func TestSlip(t *testing.T) {
+	world := newTestWorld()
+	body := newIdleBody(98) // right foot off the floor edge
+
+	var events []Event
+	body, events = Step(body, Frame{}, world, events[ : 0])
+	if !hasEvent(events, EvSlipped) || body.X != 98 + SlipSpeed || body.State != StIdle {
+		t.Fatalf("expected idle slip to x = %f, got %s at x = %f", 98 + SlipSpeed, body.State, body.X)
+	}
+
+	// keep slipping until falling off the edge
+	for tick := 0; tick < 100; tick++ {
+		body, events = Step(body, Frame{}, world, events[ : 0])
+		if body.State == StFalling { break }
+	}
+	if body.State != StFalling || body.X <= 100 - 7 {
+		t.Fatalf("expected fall after slipping off the edge, got %s at x = %f", body.State, body.X)
+	}
+
+	// slip jumps keep the jump but emit a different event
+	body = newIdleBody(98)
+	body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+	if !hasEvent(events, EvSlipJumped) || hasEvent(events, EvJumped) || body.State != StJumpingHold {
+		t.Fatalf("expected slip jump, got %s with events %v", body.State, events)
+	}
+
+	// no slip with both feet on the floor
+	body = newIdleBody(50)
+	body, events = Step(body, Frame{}, world, events[ : 0])
+	if hasEvent(events, EvSlipped) || body.X != 50 {
+		t.Fatalf("unexpected slip at x = %f", body.X)
+	}
+}
+
+func TestDeterminism(t *testing.T) {
+	world := newTestWorld()
+	world.solids[tcsts.LayerBack] = append(world.solids[tcsts.LayerBack], image.Rect(70, 0, 90, testFloorY))
+	run := func() ([]Body, []Event) {
+		body := newIdleBody(20)
+		var bodies []Body
+		var events []Event
+		for tick := 0; tick < 600; tick++ {
+			frame := Frame{ Horz: DirRight }
+			if tick % 97 == 0 || tick % 97 == 18 { frame.JumpTrigger = true }
+			frame.JumpPressed = (tick % 97) < 30
+			if tick % 150 > 120 { frame.Horz = DirLeft }
+			body, events = Step(body, frame, world, events)
+			bodies = append(bodies, body)
+		}
+		return bodies, events
+	}
+
+	bodiesA, eventsA := run()
+	bodiesB, eventsB := run()
+	if len(eventsA) != len(eventsB) {
+		t.Fatalf("event count mismatch (%d vs %d)", len(eventsA), len(eventsB))
+	}
+	for i, _ := range eventsA {
+		if eventsA[i] != eventsB[i] { t.Fatalf("event #%d mismatch", i) }
+	}
+	for i, _ := range bodiesA {
+		if bodiesA[i] != bodiesB[i] { t.Fatalf("tick #%d body mismatch", i) }
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	world := newTestWorld()
+	var apexes [PresetCountSentinel]float64
+	for preset := uint8(0); preset < PresetCountSentinel; preset++ {
+		body := newIdleBody(40)
+		body.Profile = PresetProfile(preset)
+		_, apexes[preset] = stepJump(t, body, world, 120)
+	}
+	if apexes[PresetFloaty] >= apexes[PresetClassic] {
+		t.Fatalf("expected floaty apex (%f) above classic apex (%f)", apexes[PresetFloaty], apexes[PresetClassic])
+	}
+	if PresetProfile(PresetCountSentinel) != ProfileClassic {
+		t.Fatalf("expected classic profile for unknown presets")
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	tuned := ProfileClassic
+	tuned.RunSpeed += 0.025
+	tuned.DashTicks = 14
+	overrides := NewOverrides(ProfileClassic, tuned)
+	if _, found := overrides.Get(FieldJumpInitialSpeed); found {
+		t.Fatal("unexpected override for unchanged field")
+	}
+	if value, found := overrides.Get(FieldDashTicks); !found || value != 14 {
+		t.Fatalf("expected dash ticks override 14, got %f (found = %t)", value, found)
+	}
+
+	profile := ProfileClassic
+	overrides.Apply(&profile)
+	if profile != tuned { t.Fatalf("expected %+v, got %+v", tuned, profile) }
+	overrides = NewOverrides(tuned, tuned)
+	if !overrides.IsZero() { t.Fatal("expected no overrides for equal profiles") }
+	for field := ProfileField(0); field < ProfileFieldCountSentinel; field++ {
+		profile.Set(field, profile.Get(field) + 1)
+	}
+	if NewOverrides(tuned, profile).mask != 1 << ProfileFieldCountSentinel - 1 {
+		t.Fatal("expected every field to be overridden")
+	}
+}
+
+func TestCoyoteTime(t *testing.T) {
+	world := newTestWorld()
+	for _, lateTicks := range []int{ 1, ProfileClassic.CoyoteTicks, ProfileClassic.CoyoteTicks + 2 } {
+		// run right until falling off the floor edge
+		body := newIdleBody(80)
+		var events []Event
+		for body.State != StFalling {
+			body, events = Step(body, Frame{ Horz: DirRight }, world, events[ : 0])
+		}
+
+		for i := 1; i < lateTicks; i++ {
+			body, events = Step(body, Frame{ Horz: DirRight }, world, events[ : 0])
+		}
+		body, events = Step(body, Frame{ Horz: DirRight, JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+		expectJump := (lateTicks <= ProfileClassic.CoyoteTicks)
+		if hasEvent(events, EvJumped) != expectJump {
+			t.Fatalf("test with %d late ticks, expected jump = %t, got state %s", lateTicks, expectJump, body.State)
+		}
+	}
+
+	// jumps are not allowed after falling from a jump
+	body := newIdleBody(40)
+	var events []Event
+	body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+	for body.State != StFalling {
+		body, events = Step(body, Frame{}, world, events[ : 0])
+	}
+	body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+	if hasEvent(events, EvJumped) {
+		t.Fatalf("unexpected coyote jump after a regular jump")
+	}
+}
+
+func TestJumpBuffer(t *testing.T) {
+	world := newTestWorld()
+	for _, earlyTicks := range []int{ 2, ProfileClassic.JumpBufferTicks, ProfileClassic.JumpBufferTicks + 3 } {
+		// tap jump and press again right before landing
+		body := newIdleBody(40)
+		var frames []Frame
+		var events []Event
+		var landingTick int
+		for tick := 0; landingTick == 0; tick++ {
+			frame := Frame{ JumpTrigger: tick == 0, JumpPressed: tick == 0 }
+			frames = append(frames, frame)
+			body, events = Step(body, frame, world, events[ : 0])
+			if hasEvent(events, EvLanded) { landingTick = tick }
+		}
+
+		body = newIdleBody(40)
+		frames[landingTick - earlyTicks].JumpTrigger = true
+		frames[landingTick - earlyTicks].JumpPressed = true
+		jumps := 0
+		for _, frame := range frames {
+			body, events = Step(body, frame, world, events[ : 0])
+			if hasEvent(events, EvJumped) { jumps += 1 }
+		}
+
+		expectedJumps := 1
+		if earlyTicks <= ProfileClassic.JumpBufferTicks { expectedJumps = 2 }
+		if jumps != expectedJumps {
+			t.Fatalf("test with %d early ticks, expected %d jumps, got %d", earlyTicks, expectedJumps, jumps)
+		}
+	}
+}
+
+func TestTicTacBuffer(t *testing.T) {
+	// back wall starting a few pixels to the right of the jump
+	world := newTestWorld()
+	world.solids[tcsts.LayerBack] = append(world.solids[tcsts.LayerBack], image.Rect(52, 0, 90, testFloorY))
+	body := newIdleBody(40)
+
+	var events []Event
+	body, events = Step(body, Frame{ Horz: DirRight, JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+	body, events = Step(body, Frame{ Horz: DirRight }, world, events[ : 0])
+	ticTacked := false
+	for tick := 0; tick < ProfileClassic.JumpBufferTicks; tick++ {
+		frame := Frame{ Horz: DirRight, JumpTrigger: tick == 0, JumpPressed: true }
+		body, events = Step(body, frame, world, events[ : 0])
+		if hasEvent(events, EvTicTacked) {
+			if tick == 0 { t.Fatalf("tic-tac expected to be out of reach on the first tick") }
+			ticTacked = true
+			break
+		}
+	}
+	if !ticTacked {
+		t.Fatalf("expected buffered tic-tac")
+	}
+}
+
+func TestWallSlideAndJump(t *testing.T) {
+	world := newTestWorld()
+	world.solids[tcsts.LayerMain] = append(world.solids[tcsts.LayerMain], image.Rect(60, 0, 80, 190))
+
+	for _, enabled := range []bool{ false, true } {
+		body := newIdleBody(45)
+		if enabled { body.Abilities = AbilityWallJump }
+
+		// jump against the wall and keep pushing into it
+		var events []Event
+		slid := false
+		body, events = Step(body, Frame{ Horz: DirRight, JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+		for tick := 0; tick < 300 && !slid; tick++ {
+			body, events = Step(body, Frame{ Horz: DirRight, JumpPressed: true }, world, events[ : 0])
+			if body.State == StWallSliding {
+				slid = true
+				if !enabled { t.Fatalf("unexpected wall slide with the ability disabled") }
+			}
+		}
+		if !enabled { continue }
+		if !slid { t.Fatalf("expected wall slide") }
+
+		// slide down slowly
+		for tick := 0; tick < 20; tick++ {
+			body, events = Step(body, Frame{ Horz: DirRight }, world, events[ : 0])
+			if body.State != StWallSliding { t.Fatalf("expected to keep sliding, got %s", body.State) }
+			if body.VertSpeed < -body.Profile.WallSlideSpeed {
+				t.Fatalf("wall slide speed %f exceeds %f", body.VertSpeed, body.Profile.WallSlideSpeed)
+			}
+		}
+
+		// wall jump, even if still pressing towards the wall
+		startX, startY := body.X, body.Y
+		body, events = Step(body, Frame{ Horz: DirRight, JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+		if !hasEvent(events, EvWallJumped) || body.State != StWallJumping || body.Dir != DirLeft {
+			t.Fatalf("expected wall jump to the left, got %s with events %v", body.State, events)
+		}
+		for tick := 1; tick < body.Profile.WallJumpPushTicks; tick++ {
+			body, events = Step(body, Frame{ Horz: DirRight, JumpPressed: true }, world, events[ : 0])
+		}
+		if body.X >= startX - 10 || body.Y >= startY {
+			t.Fatalf("expected wall jump to push up and away, moved from (%f, %f) to (%f, %f)", startX, startY, body.X, body.Y)
+		}
+		body, events = Step(body, Frame{ Horz: DirRight, JumpPressed: true }, world, events[ : 0])
+		if body.State != StJumpingHold {
+			t.Fatalf("expected regular jump after the push, got %s", body.State)
+		}
+
+		// releasing the direction stops the slide
+		body = newIdleBody(51)
+		body.Abilities = AbilityWallJump
+		body.Y -= 60
+		body.State = StWallSliding
+		body, events = Step(body, Frame{}, world, events[ : 0])
+		if body.State != StFalling {
+			t.Fatalf("expected fall after releasing the wall, got %s", body.State)
+		}
+	}
+}
+
+func TestAirDash(t *testing.T) {
+	world := newTestWorld()
+	for _, enabled := range []bool{ false, true } {
+		body := newIdleBody(10)
+		if enabled { body.Abilities = AbilityAirDash }
+
+		// dashes can't be used on the ground
+		var events []Event
+		body, events = Step(body, Frame{ DashTrigger: true }, world, events[ : 0])
+		if body.State == StDashing { t.Fatalf("unexpected ground dash") }
+
+		// jump and dash right at the top
+		body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+		for tick := 0; tick < 12; tick++ {
+			body, events = Step(body, Frame{ JumpPressed: true }, world, events[ : 0])
+		}
+		startX, startY := body.X, body.Y
+		body, events = Step(body, Frame{ DashTrigger: true }, world, events[ : 0])
+		if !enabled {
+			if body.State == StDashing || hasEvent(events, EvDashed) {
+				t.Fatalf("unexpected dash with the ability disabled")
+			}
+			continue
+		}
+		if body.State != StDashing || !hasEvent(events, EvDashed) {
+			t.Fatalf("expected dash, got %s with events %v", body.State, events)
+		}
+		for tick := 1; tick < body.Profile.DashTicks; tick++ {
+			body, events = Step(body, Frame{ DashTrigger: true }, world, events[ : 0])
+		}
+		if body.State != StFalling { t.Fatalf("expected fall after the dash, got %s", body.State) }
+		if body.Y != startY { t.Fatalf("expected dash to keep height, moved from %f to %f", startY, body.Y) }
+		distance := body.Profile.DashSpeed*float64(body.Profile.DashTicks)
+		if body.X - startX != distance {
+			t.Fatalf("expected dash distance %f, got %f", distance, body.X - startX)
+		}
+
+		// only one dash per airtime
+		body, events = Step(body, Frame{ DashTrigger: true }, world, events[ : 0])
+		if body.State == StDashing { t.Fatalf("unexpected second dash in the same airtime") }
+
+		// landing resets the dash
+		for tick := 0; tick < 300 && body.State == StFalling; tick++ {
+			body, events = Step(body, Frame{}, world, events[ : 0])
+		}
+		if body.State != StIdle { t.Fatalf("expected landing, got %s", body.State) }
+		body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+		body, events = Step(body, Frame{ DashTrigger: true, JumpPressed: true }, world, events[ : 0])
+		if body.State != StDashing { t.Fatalf("expected dash after landing, got %s", body.State) }
+	}
+
+	// walls stop dashes early
+	world.solids[tcsts.LayerMain] = append(world.solids[tcsts.LayerMain], image.Rect(60, 0, 80, 190))
+	body := newIdleBody(45)
+	body.Abilities = AbilityAirDash
+	var events []Event
+	body, events = Step(body, Frame{ JumpTrigger: true, JumpPressed: true }, world, events[ : 0])
+	body, events = Step(body, Frame{ DashTrigger: true, JumpPressed: true }, world, events[ : 0])
+	for tick := 0; tick < 4 && body.State == StDashing; tick++ {
+		body, events = Step(body, Frame{ JumpPressed: true }, world, events[ : 0])
+	}
+	if body.State != StFalling || body.X + float64(DefaultBox.Width) != 60 {
+		t.Fatalf("expected dash to stop at the wall, got %s at x %f", body.State, body.X)
+	}
+}
+
+func TestAnalogRun(t *testing.T) {
+	world := newTestWorld()
+	run := func(deflection float64) float64 {
+		body := newIdleBody(10)
+		var events []Event
+		for tick := 0; tick < 40; tick++ {
+			body, events = Step(body, Frame{ Horz: DirRight, Deflection: deflection }, world, events[ : 0])
+		}
+		if body.State != StRunning { t.Fatalf("expected running, got %s", body.State) }
+		return body.X - 10
+	}
+
+	full, half := run(0), run(0.5)
+	if run(1.0) != full { t.Fatalf("expected full deflection to match digital input") }
+	if half >= full*0.6 || half <= full*0.4 {
+		t.Fatalf("expected half deflection to run about half as far, got %f vs %f", half, full)
+	}
+}
+
+func TestSlopes(t *testing.T) {
+	// floor, ramp going up to the right and plateau
+	world := newTestWorld()
+	main := tcsts.LayerMain
+	world.ramps[main] = append(world.ramps[main], testRamp{ Area: image.Rect(100, 160, 140, 200) })
+	world.solids[main] = append(world.solids[main], image.Rect(140, 160, 200, 200))
+
+	run := func(body Body, dir Dir, until func(*Body) bool) Body {
+		var events []Event
+		for tick := 0; tick < 1000; tick++ {
+			body, events = Step(body, Frame{ Horz: dir }, world, events[ : 0])
+			if body.State != StRunning {
+				t.Fatalf("expected to keep running at (%f, %f), got %s", body.X, body.Y, body.State)
+			}
+			if until(&body) { return body }
+		}
+		t.Fatalf("run didn't finish after 1000 ticks")
+		return body
+	}
+
+	// run up the ramp
+	body := newIdleBody(70)
+	body = run(body, DirRight, func(body *Body) bool { return body.X >= 160 })
+	if body.Y != float64(160 - DefaultBox.Height) {
+		t.Fatalf("expected to reach the plateau, got y = %f", body.Y)
+	}
+
+	// and down again
+	body = run(body, DirLeft, func(body *Body) bool { return body.X <= 80 })
+	if body.Y != float64(testFloorY - DefaultBox.Height) {
+		t.Fatalf("expected to reach the floor, got y = %f", body.Y)
+	}
+
+	// no slips while idle on the ramp
+	body = newIdleBody(115)
+	body.Y = float64(world.ramps[main][0].surfaceAt(115 + DefaultBox.Width/2) - DefaultBox.Height)
+	var events []Event
+	for tick := 0; tick < 30; tick++ {
+		body, events = Step(body, Frame{}, world, events[ : 0])
+		if body.State != StIdle || body.X != 115 || hasEvent(events, EvSlipped) {
+			t.Fatalf("expected to stay idle on the ramp, got %s at (%f, %f)", body.State, body.X, body.Y)
+		}
+	}
+
+	// landing on the ramp
+	body.Y -= 30
+	body.State = StFalling
+	for tick := 0; tick < 300 && body.State == StFalling; tick++ {
+		body, events = Step(body, Frame{}, world, events[ : 0])
+	}
+	expectedY := float64(world.ramps[main][0].surfaceAt(115 + DefaultBox.Width/2) - DefaultBox.Height)
+	if body.State != StIdle || body.Y != expectedY {
+		t.Fatalf("expected landing on the ramp at y = %f, got %s at y = %f", expectedY, body.State, body.Y)
+	}
+}
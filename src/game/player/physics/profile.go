@@ -0,0 +1,113 @@
+package physics
+
+// Tunable movement values. Levels pick one of the presets, but
+// the values can also be tweaked live for testing.
+type Profile struct {
+	RunSpeed float64
+	JumpInitialSpeed float64
+	DefaultGravity float64
+	ExtraGravity float64 // applied after releasing jump early
+	MaxFallSpeed float64
+	AirExtraHorzSpeed float64
+	TicTacAirHorzSpeedMult float64
+
+	CoyoteTicks int // ticks after walking off a ledge where jumps are still allowed
+	JumpBufferTicks int // ticks a jump press is remembered for if it can't be used yet
+
+	WallSlideSpeed float64 // max fall speed while sliding down a wall
+	WallJumpPushSpeed float64
+	WallJumpPushTicks int
+
+	DashSpeed float64 // dash distance is DashSpeed*DashTicks
+	DashTicks int
+}
+
+const (
+	PresetClassic uint8 = iota
+	PresetFloaty
+	PresetTight
+	PresetCountSentinel
+)
+
+var ProfileClassic = Profile{
+	RunSpeed: 1.5/2.0,
+	JumpInitialSpeed: 2.4,
+	DefaultGravity: 0.046,
+	ExtraGravity: 0.10,
+	MaxFallSpeed: 2.4*1.33,
+	AirExtraHorzSpeed: 0.2,
+	TicTacAirHorzSpeedMult: 0.36,
+	CoyoteTicks: 8,
+	JumpBufferTicks: 10,
+	WallSlideSpeed: 0.5,
+	WallJumpPushSpeed: 1.4,
+	WallJumpPushTicks: 16,
+	DashSpeed: 3.0,
+	DashTicks: 10,
+}
+
+var ProfileFloaty = Profile{
+	RunSpeed: 1.4/2.0,
+	JumpInitialSpeed: 2.1,
+	DefaultGravity: 0.032,
+	ExtraGravity: 0.06,
+	MaxFallSpeed: 2.1*1.1,
+	AirExtraHorzSpeed: 0.26,
+	TicTacAirHorzSpeedMult: 0.5,
+	CoyoteTicks: 12,
+	JumpBufferTicks: 12,
+	WallSlideSpeed: 0.4,
+	WallJumpPushSpeed: 1.3,
+	WallJumpPushTicks: 20,
+	DashSpeed: 2.6,
+	DashTicks: 12,
+}
+
+var ProfileTight = Profile{
+	RunSpeed: 1.7/2.0,
+	JumpInitialSpeed: 2.7,
+	DefaultGravity: 0.062,
+	ExtraGravity: 0.16,
+	MaxFallSpeed: 2.7*1.4,
+	AirExtraHorzSpeed: 0.14,
+	TicTacAirHorzSpeedMult: 0.3,
+	CoyoteTicks: 6,
+	JumpBufferTicks: 8,
+	WallSlideSpeed: 0.6,
+	WallJumpPushSpeed: 1.6,
+	WallJumpPushTicks: 12,
+	DashSpeed: 3.4,
+	DashTicks: 8,
+}
+
+// Optional moves, enabled per map.
+type Abilities uint8
+const (
+	AbilityWallJump Abilities = 1 << iota
+	AbilityAirDash
+)
+
+func (self Abilities) Has(ability Abilities) bool {
+	return self & ability != 0
+}
+
+// Returns the profile for the given preset, or the classic
+// profile if the preset is unknown.
+func PresetProfile(preset uint8) Profile {
+	switch preset {
+	case PresetFloaty: return ProfileFloaty
+	case PresetTight : return ProfileTight
+	default:
+		return ProfileClassic
+	}
+}
+
+func PresetName(preset uint8) string {
+	switch preset {
+	case PresetClassic: return "CLASSIC"
+	case PresetFloaty : return "FLOATY"
+	case PresetTight  : return "TIGHT"
+	default:
+		return "UNKNOWN"
+	}
+}
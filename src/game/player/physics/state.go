@@ -1,4 +1,4 @@
-package player
+package physics
 
 type State uint8
 const (
@@ -9,6 +9,9 @@ const (
 	StTicTacHold
 	StTicTacInertial
 	StFalling
+	StWallSliding
+	StWallJumping // pushed away from the wall, becomes StJumpingHold after the push
+	StDashing // fixed distance horizontal burst, becomes StFalling at the end
 )
 
 func (self State) String() string {
@@ -20,6 +23,9 @@ func (self State) String() string {
 	case StTicTacHold: return "Tic-Tac (Hold)"
 	case StTicTacInertial: return "Tic-Tac (Inertial)"
 	case StFalling: return "Falling"
+	case StWallSliding: return "Wall Sliding"
+	case StWallJumping: return "Wall Jumping"
+	case StDashing: return "Dashing"
 	default:
 		return "Unknown State"
 	}
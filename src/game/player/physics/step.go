@@ -0,0 +1,692 @@
+package physics
+
+import "math"
+import "image"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+
+// Products added to other values are wrapped in explicit float64
+// conversions. Otherwise the compiler may fuse them into multiply-add
+// instructions on some architectures (arm64, ppc64, s390x...), and
+// replays would desync when verified on a different machine.
+type stepper struct {
+	Body
+	frame Frame
+	world World
+	events []Event
+}
+
+func (self *stepper) update() {
+	dir := self.frame.Horz
+	if dir != DirNone && self.State != StWallJumping { self.turnTowards(dir) }
+	if self.frame.JumpTrigger { self.JumpBufferLeft = self.Profile.JumpBufferTicks + 1 }
+	self.refreshZoneForces()
+	if self.frame.DashTrigger && self.canDash() { self.startDash() }
+
+	switch self.State {
+	case StIdle:
+		if dir == DirNone {
+			if self.detectAndProcessFalling() { break }
+			slipX := self.detectSlip()
+			if slipX != self.X { self.slipTowardsOrStartJump(slipX) }
+		} else if !self.jumpRequested() {
+			self.changeState(StRunning)
+			self.applyRunningMotion() // includes falling detection too
+		}
+
+		// jump triggering
+		if self.State == StIdle && self.jumpRequested() {
+			self.startJump()
+		}
+	case StRunning:
+		if dir == DirNone {
+			self.changeState(StIdle)
+		} else {
+			self.applyRunningMotion() // includes falling detection too
+		}
+
+		// jump triggering
+		if self.State == StRunning && self.jumpRequested() {
+			self.startJump()
+		}
+	case StJumpingHold:
+		self.applyJumpMotion(dir) // includes some state changes (top collisions, natural fall)
+		if self.State != StJumpingHold { break }
+		if self.jumpRequested() && self.canTicTac() {
+			self.startTicTac()
+		} else if !self.frame.JumpPressed {
+			self.JumpHoldStopTick = self.JumpingTicks
+			self.changeState(StJumpingInertial)
+		}
+	case StJumpingInertial:
+		self.applyJumpMotion(dir) // includes some state changes (top collisions, natural fall)
+		if self.State != StJumpingInertial { break }
+		if self.jumpRequested() && self.canTicTac() {
+			self.startTicTac()
+		}
+	case StTicTacHold:
+		self.applyJumpMotion(dir) // includes some state changes (top collisions, natural fall)
+		if self.State != StTicTacHold { break }
+		if !self.frame.JumpPressed {
+			self.changeState(StTicTacInertial)
+		}
+	case StTicTacInertial:
+		self.applyJumpMotion(dir) // includes some state changes (top collisions, natural fall)
+	case StFalling:
+		if self.CoyoteTicksLeft > 0 {
+			self.CoyoteTicksLeft -= 1
+			if self.jumpRequested() {
+				self.startJump()
+				break
+			}
+		}
+		self.applyFallMotion(dir) // includes falling/slip detection too
+		if self.State != StFalling {
+			if self.jumpRequested() { self.startJump() } // buffered or same tick jump on landing
+			break
+		}
+		if self.jumpRequested() && self.canTicTac() {
+			self.startTicTac()
+		} else if self.canWallSlide(dir) {
+			self.changeState(StWallSliding)
+		}
+	case StWallSliding:
+		if dir != self.Dir || !self.isTouchingWall() {
+			self.changeState(StFalling)
+			self.applyFallMotion(dir)
+			break
+		}
+		if self.jumpRequested() {
+			self.startWallJump()
+			break
+		}
+		self.applyFallMotion(dir) // includes landing detection
+		if self.State != StWallSliding && self.jumpRequested() {
+			self.startJump()
+		}
+	case StWallJumping:
+		self.applyJumpMotion(self.Dir) // includes some state changes (top collisions, natural fall)
+		if self.State != StWallJumping { break }
+		self.WallJumpTicksLeft -= 1
+		if !self.frame.JumpPressed {
+			self.JumpHoldStopTick = self.JumpingTicks
+			self.changeState(StJumpingInertial)
+		} else if self.WallJumpTicksLeft <= 0 {
+			self.changeState(StJumpingHold)
+		}
+	case StDashing:
+		self.applyDashMotion() // includes state change at the end of the dash
+	default:
+		panic("unknown player state")
+	}
+
+	if self.JumpBufferLeft > 0 { self.JumpBufferLeft -= 1 }
+}
+
+// The landing zone depends on the facing direction, so turning
+// around on an edge could leave the player without ground. In that
+// case, the player is moved to keep the feet in the same place.
+func (self *stepper) turnTowards(dir Dir) {
+	if dir == self.Dir { return }
+	_, iy := self.XYi()
+	grounded := (self.State == StIdle || self.State == StRunning) && self.isGroundedAt(iy + self.Box.Height)
+	self.Dir = dir
+	if !grounded || self.isGroundedAt(iy + self.Box.Height) { return }
+
+	shift := 2.0 // landing zones differ by 2 pixels
+	if dir == DirLeft { shift = -shift }
+	if !self.detectCollisionAtX(self.X + shift) {
+		self.X = min(max(self.X + shift, 0), self.maxX())
+	}
+}
+
+// Jump presses are buffered for Profile.JumpBufferTicks.
+func (self *stepper) jumpRequested() bool {
+	return self.JumpBufferLeft > 0
+}
+
+func (self *stepper) startJump() {
+	self.JumpBufferLeft = 0
+	self.emit(EvJumped)
+	self.changeState(StJumpingHold)
+}
+
+func (self *stepper) startTicTac() {
+	self.JumpBufferLeft = 0
+	self.emit(EvTicTacked)
+	self.changeState(StTicTacHold)
+}
+
+// Jumps away from the wall the player is sliding on.
+func (self *stepper) startWallJump() {
+	self.JumpBufferLeft = 0
+	switch self.Dir {
+	case DirLeft : self.Dir = DirRight
+	case DirRight: self.Dir = DirLeft
+	}
+	self.emit(EvWallJumped)
+	self.changeState(StWallJumping)
+}
+
+func (self *stepper) startDash() {
+	self.emit(EvDashed)
+	self.changeState(StDashing)
+}
+
+func (self *stepper) emit(kind EventKind) {
+	self.events = append(self.events, Event{ Kind: kind })
+}
+
+func (self *stepper) changeState(newState State) {
+	prevState := self.State
+	self.State = newState
+	self.CoyoteTicksLeft = 0
+	self.events = append(self.events, Event{ Kind: EvStateChanged, State: newState })
+
+	switch newState {
+	case StJumpingHold, StWallJumping:
+		if prevState == StWallJumping { break } // wall jump push ended, keep going
+		self.WallJumpTicksLeft = self.Profile.WallJumpPushTicks
+		self.DidTicTac = false
+		self.JumpSpeedGainLeft = self.Profile.JumpInitialSpeed
+		self.VertSpeed = 0
+		self.VertSpeed = self.nextJumpSpeed()
+		self.TicksInExtraGravity = 0
+		self.JumpingTicks = 0
+		self.JumpHoldStopTick = 9999
+	case StFalling:
+		if self.VertSpeed > 0 { // necessary for bonk cases
+			self.VertSpeed = 0
+		}
+	case StTicTacHold:
+		self.VertSpeed = float64(self.Profile.JumpInitialSpeed*0.76) - float64(math.Abs(self.VertSpeed)/8.0)
+		self.DidTicTac = true
+		self.DidDash = false
+	case StDashing:
+		self.DidDash = true
+		self.DashTicksLeft = self.Profile.DashTicks
+		self.VertSpeed = 0
+		self.TicksInExtraGravity = 0
+	case StIdle, StRunning:
+		self.VertSpeed = 0
+		self.DidDash = false
+	}
+}
+
+// Automatically changes states to falling or idle if necessary.
+// Running doesn't slip: slipping back towards an edge while running
+// away from it used to lock the player between running and falling.
+func (self *stepper) applyRunningMotion() {
+	if self.detectAndProcessFalling() { return }
+
+	switch self.Dir {
+	case DirLeft:
+		target := min(max(self.X - self.runSpeed(), 0), self.maxX())
+		for self.X != target {
+			nextX := max(math.Floor(self.X - 0.0001), target)
+			if self.detectCollisionAtX(nextX) {
+				if self.tryStepUpSlope(nextX) { continue }
+				self.changeState(StIdle)
+				break
+			} else {
+				wasOnSlope := self.isOnSlope()
+				self.X = nextX
+				self.snapDownSlope(wasOnSlope)
+			}
+		}
+	case DirRight:
+		target := min(max(self.X + self.runSpeed(), 0), self.maxX())
+		for self.X != target {
+			nextX := min(math.Ceil(self.X + 0.0001), target)
+			if self.detectCollisionAtX(nextX) {
+				if self.tryStepUpSlope(nextX) { continue }
+				self.changeState(StIdle)
+				break
+			} else {
+				wasOnSlope := self.isOnSlope()
+				self.X = nextX
+				self.snapDownSlope(wasOnSlope)
+			}
+		}
+	default:
+		panic("broken code")
+	}
+}
+
+// Automatically changes states for landing/running if necessary.
+func (self *stepper) applyFallMotion(dir Dir) {
+	// detect landing at current point for safety
+	if self.detectAndProcessLandingAtY(self.Y + float64(self.Box.Height), dir) { return }
+
+	// get target x and y coords
+	targetY := self.Y - self.nextFallSpeed()
+	targetX := self.getAirTargetX(dir)
+	moveDir := self.horzDirTowards(targetX)
+
+	// fall until reaching target or can't fall no more
+	for self.Y < targetY {
+		// apply horz movement
+		if moveDir != DirNone {
+			newX := self.X
+			switch moveDir {
+			case DirLeft  : newX = max(math.Floor(self.X - 0.0001), targetX)
+			case DirRight : newX = min(math.Ceil(self.X + 0.0001), targetX)
+			}
+			if self.detectCollisionAtX(newX) {
+				dir, moveDir = DirNone, DirNone
+			} else {
+				self.X = newX
+			}
+		}
+
+		// apply vert movement
+		self.Y = min(math.Ceil(self.Y + 0.0001), targetY)
+		if self.detectAndProcessLandingAtY(self.Y + float64(self.Box.Height), dir) { break }
+	}
+
+	// apply remaining horz movement
+	self.applyRemainingAirHorzMotion(moveDir, targetX)
+}
+
+func (self *stepper) applyRemainingAirHorzMotion(moveDir Dir, targetX float64) {
+	if moveDir == DirNone { return }
+	for self.X != targetX {
+		newX := self.X
+		switch moveDir {
+		case DirLeft  : newX = max(math.Floor(self.X - 0.0001), targetX)
+		case DirRight : newX = min(math.Ceil(self.X + 0.0001), targetX)
+		default: panic("broken code")
+		}
+		if self.detectCollisionAtX(newX) { break }
+		self.X = newX
+	}
+}
+
+// Horizontal target for jumps and falls, including wind zone effects.
+func (self *stepper) getAirTargetX(dir Dir) float64 {
+	targetX := self.X + self.ZoneWind
+	horzSpeed := self.Profile.RunSpeed + self.Profile.AirExtraHorzSpeed
+	if self.DidTicTac { horzSpeed += float64(self.Profile.AirExtraHorzSpeed*self.Profile.TicTacAirHorzSpeedMult) }
+	horzSpeed = float64(horzSpeed*self.frame.HorzFactor())
+	if self.State == StWallJumping { horzSpeed = self.Profile.WallJumpPushSpeed }
+	switch dir {
+	case DirLeft  : targetX -= horzSpeed
+	case DirRight : targetX += horzSpeed
+	}
+	return min(max(targetX, 0), self.maxX())
+}
+
+// Run speed scaled by the analog stick deflection, if any.
+func (self *stepper) runSpeed() float64 {
+	return float64(self.Profile.RunSpeed*self.frame.HorzFactor())
+}
+
+func (self *stepper) horzDirTowards(targetX float64) Dir {
+	if targetX < self.X { return DirLeft  }
+	if targetX > self.X { return DirRight }
+	return DirNone
+}
+
+func (self *stepper) endFall(dir Dir) {
+	if dir == DirNone {
+		self.changeState(StIdle)
+	} else {
+		self.changeState(StRunning)
+	}
+	self.emit(EvLanded)
+}
+
+// Automatically changes states to falling if necessary.
+func (self *stepper) applyJumpMotion(dir Dir) {
+	// get jump speed and start falling if jump speed is 0
+	self.JumpingTicks += 1
+	currentJumpSpeed := self.nextJumpSpeed()
+	if currentJumpSpeed <= 0 {
+		self.Y -= currentJumpSpeed
+		self.changeState(StFalling)
+		return
+	}
+
+	// get target x and y coords
+	targetY := self.Y - currentJumpSpeed
+	targetX := self.getAirTargetX(dir)
+	moveDir := self.horzDirTowards(targetX)
+
+	// go up until reaching target or hitting something
+	for self.Y > targetY {
+		// apply horz movement
+		newX := self.X
+		if moveDir != DirNone {
+			switch moveDir {
+			case DirLeft  : newX = max(math.Floor(self.X - 0.0001), targetX)
+			case DirRight : newX = min(math.Ceil(self.X + 0.0001), targetX)
+			}
+		}
+
+		// apply vert movement
+		newY := max(math.Floor(self.Y - 0.0001), targetY)
+		if self.detectCollisionAt(newX, newY) {
+			self.changeState(StFalling)
+			break
+		}
+		self.X, self.Y = newX, newY
+	}
+
+	// apply remaining horz movement
+	self.applyRemainingAirHorzMotion(moveDir, targetX)
+}
+
+// Dashes ignore gravity and wind. Hitting a wall ends the dash early.
+func (self *stepper) applyDashMotion() {
+	self.DashTicksLeft -= 1
+	var target float64
+	switch self.Dir {
+	case DirLeft : target = max(self.X - self.Profile.DashSpeed, 0)
+	case DirRight: target = min(self.X + self.Profile.DashSpeed, self.maxX())
+	default:
+		panic("broken code")
+	}
+
+	for self.X != target {
+		var nextX float64
+		switch self.Dir {
+		case DirLeft : nextX = max(math.Floor(self.X - 0.0001), target)
+		case DirRight: nextX = min(math.Ceil(self.X + 0.0001), target)
+		}
+		if self.detectCollisionAtX(nextX) {
+			self.changeState(StFalling)
+			return
+		}
+		self.X = nextX
+	}
+
+	if self.DashTicksLeft <= 0 {
+		self.changeState(StFalling)
+	}
+}
+
+func (self *stepper) nextJumpSpeed() float64 {
+	switch self.State {
+	case StJumpingHold, StJumpingInertial, StWallJumping:
+		return self.nextNormalJumpSpeed()
+	case StTicTacHold, StTicTacInertial:
+		return self.nextTicTacJumpSpeed()
+	default:
+		panic("broken code")
+	}
+}
+
+func (self *stepper) nextNormalJumpSpeed() float64 {
+	if self.JumpSpeedGainLeft > 0 {
+		gain := float64(self.JumpSpeedGainLeft*0.24)
+		self.VertSpeed += gain
+		self.JumpSpeedGainLeft -= gain
+	}
+	self.VertSpeed -= float64(self.Profile.DefaultGravity*self.ZoneGravityFactor)
+	if self.JumpingTicks > self.JumpHoldStopTick {
+		diff := self.JumpingTicks - self.JumpHoldStopTick
+		self.TicksInExtraGravity = max(self.TicksInExtraGravity, diff)
+		self.VertSpeed -= float64(self.Profile.ExtraGravity*self.ZoneGravityFactor)
+	}
+	return self.VertSpeed
+}
+
+func (self *stepper) nextTicTacJumpSpeed() float64 {
+	self.VertSpeed -= float64(self.Profile.DefaultGravity*0.76*self.ZoneGravityFactor)
+	if self.State == StTicTacInertial {
+		self.VertSpeed -= float64(self.Profile.DefaultGravity*self.ZoneGravityFactor)
+	}
+	return self.VertSpeed
+}
+
+func (self *stepper) nextFallSpeed() float64 {
+	self.VertSpeed -= float64(self.Profile.DefaultGravity*self.ZoneGravityFactor)
+	if self.TicksInExtraGravity > 0 {
+		self.TicksInExtraGravity -= 1
+		self.VertSpeed -= float64(self.Profile.ExtraGravity*self.ZoneGravityFactor)
+	}
+	self.VertSpeed = max(self.VertSpeed, -self.Profile.MaxFallSpeed)
+	if self.State == StWallSliding {
+		self.VertSpeed = max(self.VertSpeed, -self.Profile.WallSlideSpeed)
+	}
+
+	return self.VertSpeed
+}
+
+// Returns true if the player is starting to fall.
+func (self *stepper) detectAndProcessFalling() bool {
+	_, iy := self.XYi()
+	if self.isGroundedAt(iy + self.Box.Height) { return false }
+	self.changeState(StFalling)
+	self.CoyoteTicksLeft = self.Profile.CoyoteTicks
+	return true
+}
+
+func (self *stepper) detectCollisionAt(x, y float64) bool {
+	rect := self.CollisionRect()
+	xshift := int(x) - rect.Min.X
+	yshift := int(y) - rect.Min.Y
+	rect = rect.Add(image.Pt(xshift, yshift))
+	switch self.Layer {
+	case tcsts.LayerMain:
+		return self.world.Collides(rect, tcsts.LayerMain)
+	case tcsts.LayerFront:
+		return self.world.Collides(rect, tcsts.LayerFront)
+	default: // back layer
+		return false
+	}
+}
+
+func (self *stepper) detectCollisionAtX(x float64) bool {
+	return self.detectCollisionAt(x, self.Y)
+}
+
+func (self *stepper) detectAndProcessLandingAtY(y float64, dir Dir) bool {
+	if self.hasLandingAt(int(y), tcsts.LayerMain) {
+		self.Layer = tcsts.LayerMain
+		self.endFall(dir)
+		return true
+	} else if self.Layer != tcsts.LayerBack && self.hasLandingAt(int(y), tcsts.LayerFront) {
+		self.Layer = tcsts.LayerFront
+		self.endFall(dir)
+		return true
+	}
+	return false
+}
+
+// Returns the slip x, which will be == self.X if no slip is happening.
+func (self *stepper) detectSlip() float64 {
+	lox, lfx, rox, rfx, y := self.getFootLandingZones()
+	if self.world.IsSlopeAt(self.footX(), y, self.Layer) { return self.X } // no slips on slopes
+
+	switch self.Dir {
+	case DirRight:
+		if !self.world.HasLandingFor(rox, rfx, y, self.Layer) {
+			return min(max(self.X + SlipSpeed, 0), self.maxX())
+		} else if !self.world.HasLandingFor(lox, lfx, y, self.Layer) {
+			return min(max(self.X - SlipSpeed, 0), self.maxX())
+		} else {
+			return self.X
+		}
+	case DirLeft:
+		if !self.world.HasLandingFor(lox, lfx, y, self.Layer) {
+			return min(max(self.X - SlipSpeed, 0), self.maxX())
+		} else if !self.world.HasLandingFor(rox, rfx, y, self.Layer) {
+			return min(max(self.X + SlipSpeed, 0), self.maxX())
+		} else {
+			return self.X
+		}
+	default:
+		panic("broken code")
+	}
+}
+
+// Slips towards the given x, which must be at dist <= 1.0 from self.X.
+// It automatically detects collisions to avoid slips if necessary.
+func (self *stepper) slipTowardsOrStartJump(slipX float64) {
+	// safety assertions
+	slipX = min(max(slipX, 0), self.maxX())
+	diff := slipX - self.X
+	if diff < 0 { diff = -diff }
+	if diff > 1.0 { panic("precondition violation") }
+
+	// only slip or jump if we don't collide going towards slipX
+	if !self.detectCollisionAtX(slipX) {
+		self.X = slipX
+		self.emit(EvSlipped)
+		if self.jumpRequested() {
+			self.JumpBufferLeft = 0
+			self.emit(EvSlipJumped)
+			self.changeState(StJumpingHold)
+		}
+	}
+}
+
+func (self *stepper) refreshZoneForces() {
+	self.ZoneWind, self.ZoneGravityFactor = 0.0, 1.0
+	ix, iy := self.XYi()
+	cx, cy := ix + self.Box.Width/2, iy + self.Box.Height/2
+	if cx < 0 || cy < 0 { return }
+
+	id, found := self.world.ZoneAt(cx, cy)
+	if !found { return }
+	switch id {
+	case tcsts.ZoneWindRight: self.ZoneWind = +WindZoneSpeed
+	case tcsts.ZoneWindLeft : self.ZoneWind = -WindZoneSpeed
+	case tcsts.ZoneUpdraft  : self.ZoneGravityFactor = UpdraftGravityFactor
+	}
+}
+
+func (self *stepper) canWallSlide(dir Dir) bool {
+	if !self.Abilities.Has(AbilityWallJump) { return false }
+	if dir == DirNone || dir != self.Dir { return false }
+	return self.isTouchingWall()
+}
+
+// Reports whether there's a same layer wall right in front
+// of the player.
+func (self *stepper) isTouchingWall() bool {
+	switch self.Dir {
+	case DirLeft : return self.detectCollisionAtX(self.X - 1)
+	case DirRight: return self.detectCollisionAtX(self.X + 1)
+	default:
+		panic("broken code")
+	}
+}
+
+// Dashes can only be started while airborne, once per airtime.
+func (self *stepper) canDash() bool {
+	if !self.Abilities.Has(AbilityAirDash) || self.DidDash { return false }
+	switch self.State {
+	case StJumpingHold, StJumpingInertial, StTicTacHold, StTicTacInertial, StFalling, StWallJumping:
+		return true
+	default:
+		return false
+	}
+}
+
+// Landing zone checks plus the single foot column used by slopes.
+func (self *stepper) hasLandingAt(y int, layer int) bool {
+	ox, fx, _ := self.getLandingZone()
+	if self.world.HasLandingFor(ox, fx, y, layer) { return true }
+	footX := self.footX()
+	return self.world.HasLandingFor(footX, footX, y, layer)
+}
+
+func (self *stepper) isGroundedAt(y int) bool {
+	if self.hasLandingAt(y, tcsts.LayerMain) { return true }
+	return self.Layer != tcsts.LayerBack && self.hasLandingAt(y, tcsts.LayerFront)
+}
+
+// Slopes are only checked at the center column of the player.
+func (self *stepper) footX() int {
+	return int(self.X) + self.Box.Width/2
+}
+
+// Climbs up to nextX if the player is running on a slope (or into
+// one) and the collision can be avoided by going up a few pixels.
+func (self *stepper) tryStepUpSlope(nextX float64) bool {
+	_, iy := self.XYi()
+	groundY := iy + self.Box.Height
+	if !self.isOnSlope() {
+		nextFootX := int(nextX) + self.Box.Width/2
+		if !self.world.IsSlopeAt(nextFootX, groundY - 1, self.Layer) { return false }
+	}
+
+	for dy := 1.0; dy <= SlopeSnapDistance; dy++ {
+		if !self.detectCollisionAt(nextX, self.Y - dy) {
+			self.X, self.Y = nextX, self.Y - dy
+			return true
+		}
+	}
+	return false
+}
+
+func (self *stepper) isOnSlope() bool {
+	_, iy := self.XYi()
+	return self.world.IsSlopeAt(self.footX(), iy + self.Box.Height, self.Layer)
+}
+
+// Keeps the player grounded while running down slopes (or off
+// them) instead of falling a few pixels at every step.
+func (self *stepper) snapDownSlope(wasOnSlope bool) {
+	_, iy := self.XYi()
+	groundY := iy + self.Box.Height
+	if self.isGroundedAt(groundY) { return }
+	footX := self.footX()
+	for y := groundY + 1; y <= groundY + SlopeSnapDistance; y++ {
+		onSlope := self.world.IsSlopeAt(footX, y, self.Layer) && self.world.HasLandingFor(footX, footX, y, self.Layer)
+		if onSlope || (wasOnSlope && self.isGroundedAt(y)) {
+			self.Y += float64(y - groundY)
+			return
+		}
+	}
+}
+
+// Landing zones are two pixels narrower than the collision box,
+// and shifted depending on the facing direction (the feet aren't
+// centered in the sprites).
+func (self *stepper) getLandingZone() (ox, fx, y int) {
+	ix, iy := self.XYi()
+	switch self.Dir {
+	case DirRight : return ix + 0, ix + self.Box.Width - 2, iy + self.Box.Height
+	case DirLeft  : return ix + 2, ix + self.Box.Width, iy + self.Box.Height
+	default:
+		panic("broken code")
+	}
+}
+
+func (self *stepper) getFootLandingZones() (lox, lfx, rox, rfx, y int) {
+	ox, fx, y := self.getLandingZone()
+	half := (fx - ox)/2
+	return ox, ox + half + 1, fx - half - 1, fx, y
+}
+
+func (self *stepper) getTicTacRect() image.Rectangle {
+	ix, iy := self.XYi()
+	return image.Rect(ix + 2, iy + self.Box.Height - 8, ix + self.Box.Width - 2, iy + self.Box.Height - 3)
+}
+
+func (self *stepper) maxX() float64 {
+	return float64(640 - self.Box.Width)
+}
+
+func (self *stepper) canTicTac() bool {
+	if self.DidTicTac { return false }
+	if self.State == StFalling && self.VertSpeed < -self.Profile.JumpInitialSpeed { return false }
+
+	rect := self.getTicTacRect()
+	switch self.Layer {
+	case tcsts.LayerMain:
+		if self.world.Collides(rect, tcsts.LayerBack) {
+			self.Layer = tcsts.LayerBack
+			return true
+		}
+	case tcsts.LayerFront:
+		if self.world.Collides(rect, tcsts.LayerMain) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,22 @@
+package player
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/material/au"
+
+// Subscriber that plays the default player sound effects.
+type SoundEffects struct{}
+
+func (SoundEffects) HandlePlayerEvent(ctx *context.Context, event Event) {
+	switch event.Kind {
+	case EventJumped, EventWallJumped:
+		ctx.Audio.PlaySFX(au.SfxJump)
+	case EventTicTacked, EventDashed:
+		ctx.Audio.PlaySFX(au.SfxTicTac)
+	case EventLanded:
+		ctx.Audio.PlaySFX(au.SfxLand)
+	case EventStep:
+		ctx.Audio.PlaySFX(au.SfxStep)
+	case EventLowStep:
+		ctx.Audio.PlaySFX(au.SfxLowStep)
+	}
+}
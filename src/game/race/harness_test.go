@@ -0,0 +1,170 @@
+package race
+
+import "fmt"
+import "strings"
+import "strconv"
+import "testing"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile"
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+import "github.com/tinne26/luckyfeet/src/game/player/physics"
+import "github.com/tinne26/luckyfeet/src/game/carrot"
+
+// Scripted input test harness. Scenarios run the player body
+// through the same tilemap world adapter used while playing,
+// but without a window, animations or sound.
+//
+// Maps are either regular exported map strings or small ascii
+// layouts using the legend below, with rows starting at the top
+// of the screen:
+//   '.' empty                   '#' main ground (no landing)
+//   '~' main grass              '-' front grass
+//   '_' main single platform    'b' back ground
+//   '/', '\' main 45 deg. slopes
+//   'S' main grass with the start point on it
+//
+// Inputs are comma separated "<action> <ticks>" entries, where
+// action is one of right, left, jump, tictac (same as jump, but
+// reads better when jumping off a back wall) or dash, and ticks is
+// either a single tick "41" or an inclusive range "0-40". Jumps
+// are triggered on the first tick of the range and held until the
+// last one.
+type scenario struct {
+	Name string
+	Map string
+	Props tile.Props
+	Inputs string
+	Ticks int
+
+	WantX, WantY int // top-left corner of the collision rect
+	WantState physics.State
+	WantLayer int
+}
+
+var asciiTileIDs = map[rune]tile.Tile{
+	'#': { ID: tcsts.MainGround },
+	'~': { ID: tcsts.MainGrassSide },
+	'S': { ID: tcsts.MainGrassSide },
+	'_': { ID: tcsts.MainSinglePlatform },
+	'-': { ID: tcsts.FrontGrassSide },
+	'b': { ID: tcsts.BackGround },
+	'/': { ID: tcsts.MainSlope45 },
+	'\\': { ID: tcsts.MainSlope45, Orientation: tile.Orientation(0).Mirrored() },
+}
+
+func loadScenarioMap(data string) (*tile.Map, error) {
+	if !strings.Contains(data, "\n") {
+		return tile.LoadMapFromString(data)
+	}
+
+	tilemap := tile.NewMap(1)
+	foundStart := false
+	lines := strings.Split(strings.Trim(data, "\n"), "\n")
+	if len(lines) > tile.GridRows { return nil, fmt.Errorf("map has %d rows, max is %d", len(lines), tile.GridRows) }
+	for row, line := range lines {
+		line = strings.TrimSpace(line)
+		if len(line) > tile.GridCols { return nil, fmt.Errorf("map row %d has %d columns, max is %d", row, len(line), tile.GridCols) }
+		for col, char := range line {
+			if char == '.' { continue }
+			if char == 'S' {
+				tilemap.StartRow, tilemap.StartCol = uint8(row), uint8(col)
+				foundStart = true
+			}
+			newTile, found := asciiTileIDs[char]
+			if !found { return nil, fmt.Errorf("unexpected map char '%c' at row %d, column %d", char, row, col) }
+			newTile.Row, newTile.Column = uint8(row), uint8(col)
+			layer := tcsts.LayerMain
+			switch newTile.ID {
+			case tcsts.FrontGrassSide: layer = tcsts.LayerFront
+			case tcsts.BackGround    : layer = tcsts.LayerBack
+			}
+			tilemap.SetTile(newTile, layer)
+		}
+	}
+	if !foundStart { return nil, fmt.Errorf("map is missing the start point 'S'") }
+	return tilemap, nil
+}
+
+func parseTimeline(inputs string, ticks int) ([]physics.Frame, error) {
+	frames := make([]physics.Frame, ticks)
+	for _, entry := range strings.Split(inputs, ",") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 { continue }
+		if len(fields) != 2 { return nil, fmt.Errorf("invalid input entry '%s'", entry) }
+		from, to, err := parseTickRange(fields[1])
+		if err != nil { return nil, err }
+		if to >= ticks { return nil, fmt.Errorf("input entry '%s' goes beyond tick %d", entry, ticks - 1) }
+
+		for tick := from; tick <= to; tick++ {
+			frame := &frames[tick]
+			switch fields[0] {
+			case "right":
+				frame.Horz = physics.DirRight
+			case "left":
+				frame.Horz = physics.DirLeft
+			case "jump", "tictac":
+				frame.JumpTrigger = frame.JumpTrigger || (tick == from)
+				frame.JumpPressed = true
+			case "dash":
+				frame.DashTrigger = frame.DashTrigger || (tick == from)
+			default:
+				return nil, fmt.Errorf("unknown input action '%s'", fields[0])
+			}
+		}
+	}
+	return frames, nil
+}
+
+func parseTickRange(str string) (from, to int, err error) {
+	fromStr, toStr, isRange := strings.Cut(str, "-")
+	from, err = strconv.Atoi(fromStr)
+	if err != nil || from < 0 { return 0, 0, fmt.Errorf("invalid tick '%s'", str) }
+	if !isRange { return from, from, nil }
+	to, err = strconv.Atoi(toStr)
+	if err != nil || to < from { return 0, 0, fmt.Errorf("invalid tick range '%s'", str) }
+	return from, to, nil
+}
+
+// Runs the scenario and returns the final body, configured from
+// the map props like the play scene does.
+func runScenario(t *testing.T, test *scenario) physics.Body {
+	tilemap, err := loadScenarioMap(test.Map)
+	if err != nil { t.Fatalf("%s: %s", test.Name, err) }
+	if test.Props != (tile.Props{}) { tilemap.Props = test.Props }
+	frames, err := parseTimeline(test.Inputs, test.Ticks)
+	if err != nil { t.Fatalf("%s: %s", test.Name, err) }
+
+	body := physics.NewBody()
+	body.Profile = physics.PresetProfile(tilemap.Props.PhysicsPreset)
+	tilemap.Props.Physics.Apply(&body.Profile)
+	if tilemap.Props.WallJumps { body.Abilities |= physics.AbilityWallJump }
+	if tilemap.Props.AirDash   { body.Abilities |= physics.AbilityAirDash  }
+	body.Respawn(tilemap.StartRow, tilemap.StartCol, spawnLayer(tilemap))
+
+	world := world{ carrots: &carrot.Inventory{}, tilemap: tilemap }
+	var events []physics.Event
+	for _, frame := range frames {
+		body, events = physics.Step(body, frame, &world, events[ : 0])
+	}
+	return body
+}
+
+func TestScenarios(t *testing.T) {
+	for i, _ := range scenarios {
+		test := &scenarios[i]
+		body := runScenario(t, test)
+		x, y := body.XYi()
+		if x != test.WantX || y != test.WantY || body.State != test.WantState || body.Layer != test.WantLayer {
+			t.Errorf("%s: expected %s at (%d, %d) on layer %d, got %s at (%d, %d) on layer %d",
+				test.Name, test.WantState, test.WantX, test.WantY, test.WantLayer,
+				body.State, x, y, body.Layer)
+		}
+	}
+}
+
+func TestTimelineErrors(t *testing.T) {
+	for _, inputs := range []string{ "right", "up 3", "jump 5-2", "left 8-12", "dash -1" } {
+		_, err := parseTimeline(inputs, 10)
+		if err == nil { t.Fatalf("expected error for inputs '%s'", inputs) }
+	}
+}
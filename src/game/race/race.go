@@ -0,0 +1,243 @@
+package race
+
+import "math"
+import "image"
+import "errors"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile"
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+import "github.com/tinne26/luckyfeet/src/game/components/entity"
+import "github.com/tinne26/luckyfeet/src/game/material/characters"
+import "github.com/tinne26/luckyfeet/src/game/player/physics"
+import "github.com/tinne26/luckyfeet/src/game/carrot"
+import "github.com/tinne26/luckyfeet/src/game/replay"
+import "github.com/tinne26/luckyfeet/src/game/stats"
+
+// What happened during a race step, so the play scene can give
+// feedback (sounds, particles, transitions) without the race
+// knowing about any of it. A single step can have multiple
+// outcomes.
+type Outcome uint16
+const (
+	ManualRespawn Outcome = 1 << iota // applied before the body step
+	Died // fell or touched a critter, respawned after the body step
+	Transferred // moved to another map and respawned there
+	Finished // reached the race goal
+	PickedCarrot
+	SwitchedCarrot
+	ConsumedCarrot
+	ConsumeFailed
+)
+
+func (self Outcome) Has(outcome Outcome) bool {
+	return self & outcome != 0
+}
+
+// The deterministic part of a race: player body, carrots, critters
+// and special tiles, driven only by replay ticks. Used both by the
+// play scene and by headless replay verification, so no graphics,
+// audio or live input can be involved here.
+type Race struct {
+	maps []*tile.Map
+	mapIndex int
+	character *characters.Descriptor
+	body physics.Body
+	world world
+	events []physics.Event
+	carrots carrot.Inventory
+	actors entity.Actors
+	ticks int
+	splits []int // ticks elapsed at each map transfer
+	stats *stats.Run
+	tuned bool // if true, map physics presets and overrides are ignored
+}
+
+// Creates a race with the player already spawned on the start map.
+func New(maps []*tile.Map, startMap int, character *characters.Descriptor) (*Race, error) {
+	if startMap < 0 || startMap >= len(maps) { return nil, errors.New("start map out of range") }
+	if maps[startMap] == nil { return nil, errors.New("start map is empty") }
+
+	race := &Race{ maps: maps, mapIndex: startMap, character: character, stats: stats.NewRun(len(maps)) }
+	race.carrots.Initialize()
+	race.body = physics.NewBody()
+	race.body.Box = character.Box
+	race.respawn()
+	return race, nil
+}
+
+// Advances the race by one tick. Steps after Finished are not
+// meaningful and must be avoided.
+func (self *Race) Step(tick replay.Tick) Outcome {
+	var outcome Outcome
+	if tick.Has(replay.Respawn) {
+		self.carrots.RemoveAll()
+		self.respawn()
+		outcome |= ManualRespawn
+	}
+	self.ticks += 1
+	self.stats.MapTicks[self.mapIndex] += 1
+
+	horzAxis := tick.HorzAxis()
+	frame := physics.Frame{
+		Horz: toPhysicsAxisDir(horzAxis),
+		Deflection: math.Abs(horzAxis),
+		JumpTrigger: tick.Has(replay.JumpTrigger),
+		JumpPressed: tick.Has(replay.JumpPressed),
+		DashTrigger: tick.Has(replay.DashTrigger),
+	}
+	tilemap := self.maps[self.mapIndex]
+	self.world = world{ carrots: &self.carrots, tilemap: tilemap }
+	self.body, self.events = physics.Step(self.body, frame, &self.world, self.events[ : 0])
+	self.actors.Update(&self.carrots, tilemap)
+	self.countEvents()
+
+	if self.body.HasFallen() || self.actors.Touches(self.SpecialRect(), self.body.Layer) {
+		if self.body.HasFallen() { self.stats.Falls += 1 }
+		self.carrots.RemoveAll()
+		self.respawn()
+		outcome |= Died
+	} else {
+		outcome |= self.specialUpdate()
+		if outcome.Has(Finished) || outcome.Has(Transferred) { return outcome }
+	}
+
+	actions := self.carrots.Update(tick)
+	if actions & carrot.Switched != 0 { outcome |= SwitchedCarrot }
+	if actions & carrot.Consumed != 0 {
+		self.stats.Eat(uint8(self.carrots.Carrots[self.carrots.ActiveIndex].Variety))
+		outcome |= ConsumedCarrot
+	}
+	if actions & carrot.ConsumeFailed != 0 { outcome |= ConsumeFailed }
+	return outcome
+}
+
+// Rect used for special tiles (goal, carrots, transfers) and critters.
+func (self *Race) SpecialRect() image.Rectangle {
+	ix, iy := self.body.XYi()
+	w, h := self.body.Box.Width, self.body.Box.Height
+	switch self.body.Dir {
+	case physics.DirRight : return image.Rect(ix + 1, iy + 3, ix + w - 3, iy + h - 6)
+	case physics.DirLeft  : return image.Rect(ix + 3, iy + 3, ix + w - 1, iy + h - 6)
+	default:
+		panic("broken code")
+	}
+}
+
+func (self *Race) Body() *physics.Body { return &self.body }
+func (self *Race) Events() []physics.Event { return self.events }
+func (self *Race) Map() *tile.Map { return self.maps[self.mapIndex] }
+func (self *Race) MapIndex() int { return self.mapIndex }
+func (self *Race) Carrots() *carrot.Inventory { return &self.carrots }
+func (self *Race) Actors() *entity.Actors { return &self.actors }
+func (self *Race) Ticks() int { return self.ticks }
+func (self *Race) Splits() []int { return self.splits }
+func (self *Race) Stats() *stats.Run { return self.stats }
+
+// The returned profile can be modified directly for live tuning,
+// but SetTuned() must be called for changes to survive respawns.
+func (self *Race) Profile() *physics.Profile { return &self.body.Profile }
+func (self *Race) SetProfile(profile physics.Profile) {
+	self.body.Profile = profile
+	self.tuned = true
+}
+func (self *Race) SetTuned() { self.tuned = true }
+func (self *Race) Tuned() bool { return self.tuned }
+
+// Goes back to the profile given by the character and map.
+func (self *Race) ResetProfile() {
+	self.tuned = false
+	self.body.Profile = self.MapProfile()
+}
+
+// Returns the profile given by the character and the current map,
+// including the map physics overrides.
+func (self *Race) MapProfile() physics.Profile {
+	props := &self.maps[self.mapIndex].Props
+	profile := self.character.Profile(props.PhysicsPreset)
+	props.Physics.Apply(&profile)
+	return profile
+}
+
+// --- private methods ----
+
+func (self *Race) respawn() {
+	tilemap := self.maps[self.mapIndex]
+	if !self.tuned { self.body.Profile = self.MapProfile() }
+	var abilities physics.Abilities
+	if tilemap.Props.WallJumps { abilities |= physics.AbilityWallJump }
+	if tilemap.Props.AirDash   { abilities |= physics.AbilityAirDash  }
+	self.body.Abilities = abilities
+	self.body.Respawn(tilemap.StartRow, tilemap.StartCol, spawnLayer(tilemap))
+	self.actors.Respawn(tilemap)
+}
+
+func (self *Race) specialUpdate() Outcome {
+	tilemap := self.maps[self.mapIndex]
+	specialTile, found := tilemap.GetFirstCollision(&self.carrots, self.SpecialRect(), tcsts.LayerSpecial)
+	if !found { return 0 }
+
+	switch specialTile.ID {
+	case tcsts.RaceGoal:
+		return Finished
+	case tcsts.CarrotOrange:
+		return self.tryPick(carrot.Orange, specialTile)
+	case tcsts.CarrotYellow:
+		return self.tryPick(carrot.Yellow, specialTile)
+	case tcsts.CarrotPurple:
+		return self.tryPick(carrot.Purple, specialTile)
+	default:
+		if specialTile.ID >= tcsts.TransferUp && specialTile.ID <= tcsts.TransferDownC {
+			var targetMapID uint8
+			switch specialTile.ID & 0b11 { // could be shortened with [(tile.ID & 0b11) - 1]
+			case 1: targetMapID = tilemap.TransferIDs[0] // A
+			case 2: targetMapID = tilemap.TransferIDs[1] // B
+			case 3: targetMapID = tilemap.TransferIDs[2] // C
+			default:
+				panic("broken code")
+			}
+			if targetMapID == 0 {
+				panic("map transfer doesn't have a target defined")
+			}
+
+			self.mapIndex = int(targetMapID - 1) // this is not safe, but I have bigger problems in my life
+			self.splits = append(self.splits, self.ticks)
+			self.stats.Transfers += 1
+			self.respawn()
+			return Transferred
+		}
+	}
+
+	return 0
+}
+
+func (self *Race) tryPick(variety carrot.Variety, origin tile.Tile) Outcome {
+	carr := carrot.Carrot{ Variety: variety, OriginCol: origin.Column, OriginRow: origin.Row }
+	if !self.carrots.TryAdd(carr) { return 0 }
+	self.stats.Collect(uint8(variety))
+	return PickedCarrot
+}
+
+func (self *Race) countEvents() {
+	for _, event := range self.events {
+		switch event.Kind {
+		case physics.EvJumped, physics.EvSlipJumped, physics.EvWallJumped:
+			self.stats.Jumps += 1
+		case physics.EvTicTacked:
+			self.stats.TicTacs += 1
+		}
+	}
+}
+
+// Players start on the front layer if the start point is
+// covered by a front tile, or on the main layer otherwise.
+func spawnLayer(tilemap *tile.Map) int {
+	_, hasFrontTile := tilemap.GetTileIDAt(tilemap.StartRow, tilemap.StartCol, tcsts.LayerFront)
+	if hasFrontTile { return tcsts.LayerFront }
+	return tcsts.LayerMain
+}
+
+func toPhysicsAxisDir(horzAxis float64) physics.Dir {
+	if horzAxis > 0 { return physics.DirRight }
+	if horzAxis < 0 { return physics.DirLeft  }
+	return physics.DirNone
+}
@@ -0,0 +1,185 @@
+package race
+
+import "math"
+import "testing"
+import "errors"
+import "hash/fnv"
+import "encoding/binary"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile"
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+import "github.com/tinne26/luckyfeet/src/game/material/characters"
+import "github.com/tinne26/luckyfeet/src/game/material/level"
+import "github.com/tinne26/luckyfeet/src/game/player/physics"
+import "github.com/tinne26/luckyfeet/src/game/replay"
+
+const goalMap = `
+................
+................
+.S~~~~~~~~~~~~~.
+.##############.
+`
+
+func newGoalMap(t *testing.T) (*tile.Map, characters.Descriptor) {
+	tilemap, err := loadScenarioMap(goalMap)
+	if err != nil { t.Fatal(err) }
+	tilemap.SetTile(tile.Tile{ ID: tcsts.RaceGoal, Row: 1, Column: 8 }, tcsts.LayerSpecial)
+	desc, err := characters.ParseDescriptor("name: TEST\nsheet: test.png\nframe: 15 35\nbox: 9 28 3 7\n")
+	if err != nil { t.Fatal(err) }
+	return tilemap, desc
+}
+
+func newGoalRace(t *testing.T) *Race {
+	tilemap, desc := newGoalMap(t)
+	race, err := New([]*tile.Map{ tilemap }, 0, &desc)
+	if err != nil { t.Fatal(err) }
+	return race
+}
+
+// Runs right until the goal and returns the clear ticks.
+func runToGoal(t *testing.T, race *Race, respawnAt int) int {
+	for i := 0; i < 1000; i++ {
+		tick := replay.NewTick(1.0, 0)
+		if i == respawnAt { tick.Buttons |= replay.Respawn }
+		if race.Step(tick).Has(Finished) { return race.Ticks() }
+	}
+	t.Fatal("goal not reached")
+	return 0
+}
+
+func TestFinish(t *testing.T) {
+	race := newGoalRace(t)
+	clearTicks := runToGoal(t, race, -1)
+	if clearTicks != runToGoal(t, newGoalRace(t), -1) {
+		t.Fatal("simulation is not deterministic")
+	}
+	stats := race.Stats()
+	if stats.MapTicks[0] != clearTicks || stats.Jumps != 0 || stats.Falls != 0 {
+		t.Fatalf("unexpected run stats %+v", stats)
+	}
+
+	// manual respawns send the player back to the start
+	respawnTicks := runToGoal(t, newGoalRace(t), 20)
+	if respawnTicks != clearTicks + 20 {
+		t.Fatalf("expected %d ticks with respawn, got %d", clearTicks + 20, respawnTicks)
+	}
+}
+
+func TestMapOverrides(t *testing.T) {
+	tilemap, desc := newGoalMap(t)
+	tilemap.Props.PhysicsPreset = physics.PresetFloaty
+	tilemap.Props.Physics.Set(physics.FieldRunSpeed, 1.0)
+	race, err := New([]*tile.Map{ tilemap }, 0, &desc)
+	if err != nil { t.Fatal(err) }
+	want := physics.ProfileFloaty
+	want.RunSpeed = 1.0
+	if *race.Profile() != want { t.Fatalf("expected %+v, got %+v", want, *race.Profile()) }
+
+	race.SetProfile(physics.ProfileTight)
+	race.ResetProfile()
+	if *race.Profile() != want { t.Fatalf("expected %+v after reset, got %+v", want, *race.Profile()) }
+}
+
+func TestStartMapOutOfRange(t *testing.T) {
+	var desc characters.Descriptor
+	_, err := New([]*tile.Map{ tile.NewMap(1) }, 1, &desc)
+	if err == nil { t.Fatal("expected error") }
+}
+
+func TestVerify(t *testing.T) {
+	tilemap, desc := newGoalMap(t)
+	packData, err := tilemap.ExportToString()
+	if err != nil { t.Fatal(err) }
+	descs := []characters.Descriptor{ desc }
+	clearTicks := runToGoal(t, newGoalRace(t), -1)
+
+	run := &replay.Replay{ PackHash: level.PackHash(packData), Character: desc.Name, ClearTicks: clearTicks }
+	for i := 0; i < clearTicks; i++ { run.Append(replay.NewTick(1.0, 0)) }
+	verified, err := Verify(run, packData, descs)
+	if err != nil || verified != clearTicks {
+		t.Fatalf("expected %d ticks, got %d (err = %v)", clearTicks, verified, err)
+	}
+
+	failures := []struct{ name string; edit func(*replay.Replay); want error }{
+		{ "pack", func(r *replay.Replay) { r.PackHash += 1 }, ErrPackMismatch },
+		{ "claim", func(r *replay.Replay) { r.ClearTicks -= 1 }, ErrDesync },
+		{ "extra", func(r *replay.Replay) { r.Append(replay.Tick{}) }, ErrDesync },
+		{ "short", func(r *replay.Replay) { r.Ticks = r.Ticks[ : len(r.Ticks) - 1] }, ErrNotFinished },
+	}
+	for _, failure := range failures {
+		edited := *run
+		edited.Ticks = append([]replay.Tick(nil), run.Ticks...)
+		failure.edit(&edited)
+		_, err := Verify(&edited, packData, descs)
+		if !errors.Is(err, failure.want) || !IsVerifyFailure(err) {
+			t.Fatalf("%s: expected %v, got %v", failure.name, failure.want, err)
+		}
+	}
+
+	// characters are looked up by name, so unknown or missing names
+	// can't be verified
+	for _, name := range []string{ "", "UNKNOWN" } {
+		edited := *run
+		edited.Character = name
+		_, err := Verify(&edited, packData, descs)
+		if err == nil || IsVerifyFailure(err) {
+			t.Fatalf("character '%s': expected a non verification error, got %v", name, err)
+		}
+	}
+}
+
+// Fixed replay for the layers map, with short and long jumps,
+// partial axis values and a tic-tac onto the goal ledge. Expected
+// values were obtained without fused multiply-adds, so running this
+// with GOARCH=arm64 (or GOAMD64=v3) catches products that the
+// compiler is allowed to fuse, which would make replays recorded
+// on one architecture desync on another.
+func TestGoldenReplay(t *testing.T) {
+	const wantClearTicks = 299
+	const wantTraceHash = 0x588e86aeee71c8e6
+
+	tilemap, err := loadScenarioMap(layersMap)
+	if err != nil { t.Fatal(err) }
+	tilemap.SetTile(tile.Tile{ ID: tcsts.RaceGoal, Row: 9, Column: 13 }, tcsts.LayerSpecial)
+	_, desc := newGoalMap(t)
+	jump := replay.JumpTrigger | replay.JumpPressed
+	runs := []struct{ axis float64; buttons replay.Buttons; ticks int }{
+		{ -0.6, 0, 40 }, { -0.6, jump, 1 }, { -0.6, 0, 17 },
+		{ 0.35, 0, 30 }, { 0.8, 0, 40 }, { 0.8, jump, 1 },
+		{ 1.0, replay.JumpPressed, 20 }, { 1.0, 0, 1 }, { 1.0, jump, 1 },
+		{ 1.0, replay.JumpPressed, 28 }, { 1.0, 0, 25 }, { 1.0, jump, 1 },
+		{ 1.0, replay.JumpPressed, 20 }, { 1.0, 0, 1 }, { 1.0, jump, 1 }, // tic-tac
+		{ 1.0, replay.JumpPressed, 28 }, { 1.0, 0, 44 },
+	}
+	packData, err := tilemap.ExportToString()
+	if err != nil { t.Fatal(err) }
+	run := &replay.Replay{ PackHash: level.PackHash(packData), Character: desc.Name }
+	for _, entry := range runs {
+		for i := 0; i < entry.ticks; i++ {
+			run.Append(replay.NewTick(entry.axis, entry.buttons))
+		}
+	}
+
+	clearTicks, err := Verify(run, packData, []characters.Descriptor{ desc })
+	if err != nil || clearTicks != wantClearTicks {
+		t.Fatalf("expected %d clear ticks, got %d (err = %v)", wantClearTicks, clearTicks, err)
+	}
+
+	// clear ticks can survive small rounding differences, so the
+	// body trajectory is also compared bit by bit
+	race, err := New([]*tile.Map{ tilemap }, 0, &desc)
+	if err != nil { t.Fatal(err) }
+	hash := fnv.New64a()
+	var buffer []byte
+	for _, tick := range run.Ticks {
+		race.Step(tick)
+		body := race.Body()
+		buffer = binary.BigEndian.AppendUint64(buffer[ : 0], math.Float64bits(body.X))
+		buffer = binary.BigEndian.AppendUint64(buffer, math.Float64bits(body.Y))
+		buffer = binary.BigEndian.AppendUint64(buffer, math.Float64bits(body.VertSpeed))
+		hash.Write(buffer)
+	}
+	if hash.Sum64() != wantTraceHash {
+		t.Fatalf("expected trace hash %016x, got %016x", uint64(wantTraceHash), hash.Sum64())
+	}
+}
@@ -0,0 +1,112 @@
+package race
+
+import "strings"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile"
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+import "github.com/tinne26/luckyfeet/src/game/player/physics"
+import "github.com/tinne26/luckyfeet/src/game/material/level"
+
+// Ledge on the left, with a lower floor to land on after falling.
+const edgeMap = `
+................
+.S~~............
+.###............
+................
+................
+~~~~~~~~~~~~~~~~
+################
+`
+
+// Front layer platform on the left, back layer wall in the middle
+// and a main layer ledge that can only be reached with a tic-tac.
+const layersMap = `
+................
+................
+................
+................
+................
+................
+................
+..........b.....
+..........b.....
+..........b.....
+..........b~~~~.
+...---....b.....
+..........b.....
+.~~~~S~~~~~~~~~.
+.##############.
+`
+
+// Gap too wide to jump over without dashing.
+const gapMap = `
+................
+.S~~....~~~~....
+.###....####....
+................
+~~~~~~~~~~~~~~~~
+################
+`
+
+var scenarios = []scenario{
+	{
+		Name: "run off edge", Map: edgeMap,
+		Inputs: "right 0-100", Ticks: 200,
+		WantX: 101, WantY: 83, WantState: physics.StIdle, WantLayer: tcsts.LayerMain,
+	},
+	{
+		Name: "stop on edge and slip", Map: edgeMap,
+		Inputs: "right 0-74", Ticks: 200,
+		WantX: 80, WantY: 83, WantState: physics.StIdle, WantLayer: tcsts.LayerMain,
+	},
+	{
+		Name: "stop on edge and stay", Map: edgeMap,
+		Inputs: "right 0-72", Ticks: 200,
+		WantX: 76, WantY: 3, WantState: physics.StIdle, WantLayer: tcsts.LayerMain,
+	},
+	{
+		Name: "turn around on edge", Map: edgeMap,
+		Inputs: "right 0-72, left 120-160", Ticks: 200,
+		WantX: 46, WantY: 3, WantState: physics.StIdle, WantLayer: tcsts.LayerMain,
+	},
+	{ // turning used to move the landing zone off the edge and fall
+		Name: "turn around hanging from edge", Map: edgeMap,
+		Inputs: "right 0-76, left 77-100", Ticks: 140,
+		WantX: 59, WantY: 3, WantState: physics.StIdle, WantLayer: tcsts.LayerMain,
+	},
+	{ // used to lock between running and slipping back off the edge
+		Name: "run away from edge after stopping", Map: edgeMap,
+		Inputs: "right 0-74, left 76-100", Ticks: 140,
+		WantX: 57, WantY: 3, WantState: physics.StIdle, WantLayer: tcsts.LayerMain,
+	},
+	{
+		Name: "turn around on left edge", Map: edgeMap,
+		Inputs: "left 0-2, right 40-80", Ticks: 140,
+		WantX: 50, WantY: 3, WantState: physics.StIdle, WantLayer: tcsts.LayerMain,
+	},
+	{
+		Name: "jump onto front platform", Map: layersMap,
+		Inputs: "jump 0-30", Ticks: 120,
+		WantX: 102, WantY: 203, WantState: physics.StIdle, WantLayer: tcsts.LayerFront,
+	},
+	{
+		Name: "tic-tac onto ledge", Map: layersMap,
+		Inputs: "right 0-199, jump 100-120, tictac 122-150", Ticks: 260,
+		WantX: 270, WantY: 183, WantState: physics.StIdle, WantLayer: tcsts.LayerMain,
+	},
+	{
+		Name: "jump into gap", Map: gapMap,
+		Inputs: "right 0-150, jump 70-100", Ticks: 250,
+		WantX: 150, WantY: 63, WantState: physics.StIdle, WantLayer: tcsts.LayerMain,
+	},
+	{
+		Name: "dash over gap", Map: gapMap, Props: tile.Props{ AirDash: true },
+		Inputs: "right 0-150, jump 70-100, dash 100", Ticks: 250,
+		WantX: 171, WantY: 3, WantState: physics.StIdle, WantLayer: tcsts.LayerMain,
+	},
+	{
+		Name: "guidance map run", Map: strings.Split(strings.TrimSpace(level.GetData(level.Guidance)), ".")[0],
+		Inputs: "right 0-59", Ticks: 120,
+		WantX: 127, WantY: 263, WantState: physics.StIdle, WantLayer: tcsts.LayerFront,
+	},
+}
@@ -0,0 +1,56 @@
+package race
+
+import "fmt"
+import "errors"
+import "strings"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile"
+import "github.com/tinne26/luckyfeet/src/game/material/characters"
+import "github.com/tinne26/luckyfeet/src/game/material/level"
+import "github.com/tinne26/luckyfeet/src/game/replay"
+
+// Verification failures, as opposed to errors caused by bad input
+// data. Returned errors wrap these with extra details.
+var (
+	ErrPackMismatch = errors.New("level pack hash mismatch")
+	ErrNotFinished  = errors.New("race not finished")
+	ErrDesync       = errors.New("desync")
+)
+
+// Reports whether the error means the run is invalid, rather than
+// not verifiable.
+func IsVerifyFailure(err error) bool {
+	return errors.Is(err, ErrPackMismatch) || errors.Is(err, ErrNotFinished) || errors.Is(err, ErrDesync)
+}
+
+// Simulates the replay on the given level pack data and returns
+// the clear ticks. Runs must end exactly on the goal, and match
+// the clear ticks stored in the replay, if any.
+func Verify(run *replay.Replay, packData string, descs []characters.Descriptor) (int, error) {
+	packHash := level.PackHash(packData)
+	if packHash != run.PackHash {
+		return 0, fmt.Errorf("%w (replay %016x, pack %016x)", ErrPackMismatch, run.PackHash, packHash)
+	}
+	var maps []*tile.Map
+	for _, mapData := range strings.Split(strings.TrimSpace(packData), ".") {
+		tilemap, err := tile.LoadMapFromString(mapData)
+		if err != nil { return 0, err }
+		maps = append(maps, tilemap)
+	}
+	index := characters.IndexByName(descs, run.Character)
+	if index == -1 { return 0, fmt.Errorf("replay character '%s' not found", run.Character) }
+
+	race, err := New(maps, int(run.StartMap), &descs[index])
+	if err != nil { return 0, err }
+	for i, tick := range run.Ticks {
+		if !race.Step(tick).Has(Finished) { continue }
+		if i != len(run.Ticks) - 1 {
+			return race.Ticks(), fmt.Errorf("%w, race finished with %d ticks left", ErrDesync, len(run.Ticks) - 1 - i)
+		}
+		if run.ClearTicks != 0 && run.ClearTicks != race.Ticks() {
+			return race.Ticks(), fmt.Errorf("%w, replay claims %d ticks", ErrDesync, run.ClearTicks)
+		}
+		return race.Ticks(), nil
+	}
+	return 0, fmt.Errorf("%w after %d ticks", ErrNotFinished, len(run.Ticks))
+}
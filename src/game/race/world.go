@@ -0,0 +1,34 @@
+package race
+
+import "image"
+
+import "github.com/tinne26/luckyfeet/src/game/components/tile"
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+import "github.com/tinne26/luckyfeet/src/game/player/physics"
+import "github.com/tinne26/luckyfeet/src/game/carrot"
+
+var _ physics.World = (*world)(nil)
+
+// Adapts the tilemap and carrot state to the physics.World interface.
+type world struct {
+	carrots *carrot.Inventory
+	tilemap *tile.Map
+}
+
+func (self *world) Collides(rect image.Rectangle, layer int) bool {
+	return self.tilemap.Collides(self.carrots, rect, layer)
+}
+
+func (self *world) HasLandingFor(ox, fx, y int, layer int) bool {
+	return self.tilemap.HasLandingFor(self.carrots, ox, fx, y, layer)
+}
+
+func (self *world) IsSlopeAt(x, y int, layer int) bool {
+	return self.tilemap.IsSlopeAt(x, y, layer)
+}
+
+func (self *world) ZoneAt(x, y int) (uint8, bool) {
+	if x < 0 || y < 0 { return tcsts.TileTypeMax, false }
+	row, col := uint8(min(y/20, 255)), uint8(min(x/20, 255))
+	return self.tilemap.GetTileIDAt(row, col, tcsts.LayerZone)
+}
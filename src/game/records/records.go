@@ -0,0 +1,190 @@
+package records
+
+import "fmt"
+import "sort"
+import "bufio"
+import "bytes"
+import "errors"
+import "strconv"
+import "strings"
+import "io/fs"
+
+import "github.com/tinne26/luckyfeet/src/game/storage"
+
+// Storage name for the records.
+const FileName = "records.txt"
+
+const header = "luckyfeet records v1"
+
+var ErrInvalidData = errors.New("invalid records data")
+
+// Records are kept per level pack content and start map, as the
+// same pack can be playtested from different maps.
+type Key struct {
+	PackHash uint64
+	StartMap uint8
+}
+
+// Segments are the parts of a run between map transfers. They
+// are compared by position, so runs taking different transfers
+// may not match.
+type Best struct {
+	ClearTicks int
+	Splits []int // ticks elapsed at each map transfer
+	Golds []int // best duration for each segment across all clears
+}
+
+// Returns the ticks elapsed at the end of each segment of the
+// best run.
+func (self *Best) SplitTimes() []int {
+	return append(append([]int(nil), self.Splits...), self.ClearTicks)
+}
+
+// Returns the ticks elapsed at the end of each segment if all
+// segments matched their golds.
+func (self *Best) GoldTimes() []int {
+	times := make([]int, len(self.Golds))
+	var elapsed int
+	for i, gold := range self.Golds {
+		elapsed += gold
+		times[i] = elapsed
+	}
+	return times
+}
+
+// Converts the ticks elapsed at each transfer and the clear time
+// to segment durations.
+func Segments(splits []int, clearTicks int) []int {
+	segments := make([]int, len(splits) + 1)
+	var prev int
+	for i, split := range splits {
+		segments[i] = split - prev
+		prev = split
+	}
+	segments[len(splits)] = clearTicks - prev
+	return segments
+}
+
+type Records struct {
+	bests map[Key]*Best
+}
+
+func New() *Records {
+	return &Records{ bests: make(map[Key]*Best) }
+}
+
+// Returns the best run for the given key, or nil if none.
+func (self *Records) Best(key Key) *Best {
+	return self.bests[key]
+}
+
+// Registers a clear and returns true if it's a new best. Golds
+// are updated even when it isn't.
+func (self *Records) Submit(key Key, clearTicks int, splits []int) bool {
+	segments := Segments(splits, clearTicks)
+	best := self.bests[key]
+	if best == nil {
+		self.bests[key] = &Best{
+			ClearTicks: clearTicks,
+			Splits: append([]int(nil), splits...),
+			Golds: segments,
+		}
+		return true
+	}
+
+	for i, segment := range segments {
+		if i >= len(best.Golds) {
+			best.Golds = append(best.Golds, segment)
+		} else {
+			best.Golds[i] = min(best.Golds[i], segment)
+		}
+	}
+	if best.ClearTicks <= clearTicks { return false }
+	best.ClearTicks = clearTicks
+	best.Splits = append(best.Splits[ : 0], splits...)
+	return true
+}
+
+// Loads the records from storage. If nothing was saved yet,
+// empty records are returned.
+func Load() (*Records, error) {
+	data, err := storage.Load(FileName)
+	if errors.Is(err, fs.ErrNotExist) { return New(), nil }
+	if err != nil { return nil, err }
+	return Decode(data)
+}
+
+func (self *Records) Save() error {
+	return storage.Save(FileName, self.Encode())
+}
+
+// Encodes the records as text, one line per best run:
+// "<pack hash> <start map> <clear ticks> / [split ticks...] / [golds...]".
+func (self *Records) Encode() []byte {
+	keys := make([]Key, 0, len(self.bests))
+	for key, _ := range self.bests { keys = append(keys, key) }
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].PackHash != keys[j].PackHash {
+			return keys[i].PackHash < keys[j].PackHash
+		}
+		return keys[i].StartMap < keys[j].StartMap
+	})
+
+	var buffer bytes.Buffer
+	buffer.WriteString(header + "\n")
+	for _, key := range keys {
+		best := self.bests[key]
+		fmt.Fprintf(&buffer, "%016x %d %d", key.PackHash, key.StartMap, best.ClearTicks)
+		buffer.WriteString(" /")
+		for _, split := range best.Splits {
+			fmt.Fprintf(&buffer, " %d", split)
+		}
+		buffer.WriteString(" /")
+		for _, gold := range best.Golds {
+			fmt.Fprintf(&buffer, " %d", gold)
+		}
+		buffer.WriteByte('\n')
+	}
+	return buffer.Bytes()
+}
+
+func Decode(data []byte) (*Records, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() { return nil, ErrInvalidData }
+	if scanner.Text() != header { return nil, ErrInvalidData }
+
+	records := New()
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" { continue }
+		parts := strings.Split(scanner.Text(), "/")
+		if len(parts) != 3 { return nil, ErrInvalidData }
+
+		fields := strings.Fields(parts[0])
+		if len(fields) != 3 { return nil, ErrInvalidData }
+		hash, err := strconv.ParseUint(fields[0], 16, 64)
+		if err != nil { return nil, ErrInvalidData }
+		startMap, err := strconv.ParseUint(fields[1], 10, 8)
+		if err != nil { return nil, ErrInvalidData }
+		ticks, err := parseTicks(fields[2:])
+		if err != nil { return nil, err }
+		best := &Best{ ClearTicks: ticks[0] }
+		best.Splits, err = parseTicks(strings.Fields(parts[1]))
+		if err != nil { return nil, err }
+		best.Golds, err = parseTicks(strings.Fields(parts[2]))
+		if err != nil { return nil, err }
+		key := Key{ PackHash: hash, StartMap: uint8(startMap) }
+		records.bests[key] = best
+	}
+	if scanner.Err() != nil { return nil, ErrInvalidData }
+	return records, nil
+}
+
+func parseTicks(fields []string) ([]int, error) {
+	ticks := make([]int, len(fields))
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil || value < 0 { return nil, ErrInvalidData }
+		ticks[i] = value
+	}
+	return ticks, nil
+}
@@ -0,0 +1,94 @@
+package records
+
+import "testing"
+
+func TestSubmit(t *testing.T) {
+	records := New()
+	key := Key{ PackHash: 0xABCD, StartMap: 1 }
+	if records.Best(key) != nil { t.Fatal("unexpected best on empty records") }
+	if !records.Submit(key, 1000, []int{ 300, 600 }) {
+		t.Fatal("first clear should be a new best")
+	}
+	if records.Submit(key, 1200, nil) { t.Fatal("slower clear shouldn't be a new best") }
+	if records.Submit(key, 1000, nil) { t.Fatal("tied clear shouldn't be a new best") }
+	if !records.Submit(key, 900, []int{ 250 }) {
+		t.Fatal("faster clear should be a new best")
+	}
+
+	best := records.Best(key)
+	if best.ClearTicks != 900 || len(best.Splits) != 1 || best.Splits[0] != 250 {
+		t.Fatalf("unexpected best %+v", best)
+	}
+	if !equalTicks(best.Golds, []int{ 250, 300, 400 }) {
+		t.Fatalf("unexpected golds %v", best.Golds)
+	}
+	if records.Best(Key{ PackHash: 0xABCD }) != nil {
+		t.Fatal("start maps should have separate records")
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	records := New()
+	records.Submit(Key{ PackHash: 0xFFFFFFFFFFFFFFFF, StartMap: 0 }, 4321, []int{ 1000, 2000, 3000 })
+	records.Submit(Key{ PackHash: 7, StartMap: 3 }, 120, nil)
+
+	decoded, err := Decode(records.Encode())
+	if err != nil { t.Fatal(err) }
+	if len(decoded.bests) != len(records.bests) {
+		t.Fatalf("expected %d records, got %d", len(records.bests), len(decoded.bests))
+	}
+	for key, best := range records.bests {
+		got := decoded.Best(key)
+		if got == nil { t.Fatalf("missing record for %+v", key) }
+		if got.ClearTicks != best.ClearTicks || !equalTicks(got.Splits, best.Splits) || !equalTicks(got.Golds, best.Golds) {
+			t.Fatalf("record mismatch for %+v: %+v vs %+v", key, got, best)
+		}
+	}
+}
+
+func TestGolds(t *testing.T) {
+	records := New()
+	key := Key{ PackHash: 1 }
+	records.Submit(key, 1000, []int{ 400, 700 }) // segments 400, 300, 300
+	records.Submit(key, 1100, []int{ 300, 800 }) // segments 300, 500, 300
+	records.Submit(key, 1200, []int{ 500 })      // segments 500, 700
+
+	best := records.Best(key)
+	if best.ClearTicks != 1000 || !equalTicks(best.Splits, []int{ 400, 700 }) {
+		t.Fatalf("unexpected best run %+v", best)
+	}
+	if !equalTicks(best.Golds, []int{ 300, 300, 300 }) {
+		t.Fatalf("unexpected golds %v", best.Golds)
+	}
+	if !equalTicks(best.SplitTimes(), []int{ 400, 700, 1000 }) {
+		t.Fatalf("unexpected split times %v", best.SplitTimes())
+	}
+	if !equalTicks(best.GoldTimes(), []int{ 300, 600, 900 }) {
+		t.Fatalf("unexpected gold times %v", best.GoldTimes())
+	}
+}
+
+func equalTicks(a, b []int) bool {
+	if len(a) != len(b) { return false }
+	for i, _ := range a {
+		if a[i] != b[i] { return false }
+	}
+	return true
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"not records\n",
+		header + "\n00000007 0\n",
+		header + "\nzz 0 100\n",
+		header + "\n00000007 0 -5\n",
+		header + "\n00000007 256 100 / /\n",
+		header + "\n00000007 0 100 5 / /\n",
+		header + "\n00000007 0 100 / x /\n",
+	}
+	for _, data := range invalid {
+		_, err := Decode([]byte(data))
+		if err != ErrInvalidData { t.Fatalf("expected ErrInvalidData for %q, got %v", data, err) }
+	}
+}
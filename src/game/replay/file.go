@@ -0,0 +1,25 @@
+//go:build !wasm
+
+package replay
+
+import "os"
+import "time"
+import "path/filepath"
+
+// Directory where replays are saved, relative to the working directory.
+const Dir = "replays"
+
+// Saves the replay into a new file in Dir and returns its path.
+func SaveFile(replay *Replay) (string, error) {
+	err := os.MkdirAll(Dir, 0755)
+	if err != nil { return "", err }
+	name := time.Now().Format("20060102_150405") + ".lfr"
+	path := filepath.Join(Dir, name)
+	return path, os.WriteFile(path, replay.Encode(), 0644)
+}
+
+func LoadFile(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil { return nil, err }
+	return Decode(data)
+}
@@ -0,0 +1,17 @@
+//go:build wasm
+
+package replay
+
+import "errors"
+
+var errNoFiles = errors.New("replay files are not supported on browsers")
+
+// Browsers can't save replay files. The last replay is still
+// kept in memory and can be watched until the page is closed.
+func SaveFile(replay *Replay) (string, error) {
+	return "", errNoFiles
+}
+
+func LoadFile(path string) (*Replay, error) {
+	return nil, errNoFiles
+}
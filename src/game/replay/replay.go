@@ -0,0 +1,130 @@
+package replay
+
+import "errors"
+import "encoding/binary"
+
+// Replays store the gameplay input of a run together with
+// everything else needed to reproduce it deterministically.
+//
+// Binary format: "LFRP" magic, format version byte, game version
+// (length prefixed), level pack hash (8 bytes, big endian), start
+// map index, character name (length prefixed), random seed (8
+// bytes, big endian), clear ticks (uvarint) and finally the ticks,
+// run-length encoded as a uvarint number of runs followed by
+// (uvarint length, axis, buttons) triplets.
+type Replay struct {
+	GameVersion string
+	PackHash uint64
+	StartMap uint8 // map index within the level pack
+	Character string // descriptor name
+	Seed int64
+	ClearTicks int // ticks to reach the goal, 0 if the race wasn't finished
+	Ticks []Tick
+}
+
+const magic = "LFRP"
+const formatVersion = 1
+
+var ErrInvalidData = errors.New("invalid replay data")
+
+func (self *Replay) Append(tick Tick) {
+	self.Ticks = append(self.Ticks, tick)
+}
+
+func (self *Replay) Encode() []byte {
+	data := make([]byte, 0, 64)
+	data = append(data, magic...)
+	data = append(data, formatVersion)
+	gameVersion := self.GameVersion[ : min(len(self.GameVersion), 255)]
+	data = append(data, uint8(len(gameVersion)))
+	data = append(data, gameVersion...)
+	data = binary.BigEndian.AppendUint64(data, self.PackHash)
+	data = append(data, self.StartMap)
+	character := self.Character[ : min(len(self.Character), 255)]
+	data = append(data, uint8(len(character)))
+	data = append(data, character...)
+	data = binary.BigEndian.AppendUint64(data, uint64(self.Seed))
+	data = binary.AppendUvarint(data, uint64(self.ClearTicks))
+
+	var numRuns int
+	for i, _ := range self.Ticks {
+		if i == 0 || self.Ticks[i] != self.Ticks[i - 1] { numRuns += 1 }
+	}
+	data = binary.AppendUvarint(data, uint64(numRuns))
+	for i := 0; i < len(self.Ticks); {
+		tick := self.Ticks[i]
+		runEnd := i + 1
+		for runEnd < len(self.Ticks) && self.Ticks[runEnd] == tick { runEnd += 1 }
+		data = binary.AppendUvarint(data, uint64(runEnd - i))
+		data = append(data, uint8(tick.Axis), uint8(tick.Buttons))
+		i = runEnd
+	}
+	return data
+}
+
+func Decode(data []byte) (*Replay, error) {
+	if len(data) < len(magic) + 2 || string(data[ : len(magic)]) != magic {
+		return nil, ErrInvalidData
+	}
+	data = data[len(magic) : ]
+	if data[0] != formatVersion { return nil, errors.New("unsupported replay format version") }
+	versionLen := int(data[1])
+	data = data[2 : ]
+	if len(data) < versionLen + 8 + 2 + 8 { return nil, ErrInvalidData }
+
+	replay := &Replay{}
+	replay.GameVersion = string(data[ : versionLen])
+	data = data[versionLen : ]
+	replay.PackHash = binary.BigEndian.Uint64(data)
+	replay.StartMap = data[8]
+	nameLen := int(data[9])
+	data = data[10 : ]
+	if len(data) < nameLen + 8 { return nil, ErrInvalidData }
+	replay.Character = string(data[ : nameLen])
+	data = data[nameLen : ]
+	replay.Seed = int64(binary.BigEndian.Uint64(data))
+	data = data[8 : ]
+	clearTicks, n := binary.Uvarint(data)
+	if n <= 0 || clearTicks > MaxTicks { return nil, ErrInvalidData }
+	replay.ClearTicks = int(clearTicks)
+	data = data[n : ]
+
+	numRuns, n := binary.Uvarint(data)
+	if n <= 0 { return nil, ErrInvalidData }
+	data = data[n : ]
+	for run := uint64(0); run < numRuns; run++ {
+		runLen, n := binary.Uvarint(data)
+		if n <= 0 || runLen == 0 || len(data) < n + 2 { return nil, ErrInvalidData }
+		if uint64(len(replay.Ticks)) + runLen > MaxTicks { return nil, errors.New("replay is too long") }
+		tick := Tick{ Axis: int8(data[n]), Buttons: Buttons(data[n + 1]) }
+		for i := uint64(0); i < runLen; i++ {
+			replay.Ticks = append(replay.Ticks, tick)
+		}
+		data = data[n + 2 : ]
+	}
+	if len(data) != 0 { return nil, ErrInvalidData }
+	return replay, nil
+}
+
+// Two hours at 120 ticks per second.
+const MaxTicks = 120*60*60*2
+
+// Feeds the ticks of a replay back in order.
+type Playback struct {
+	replay *Replay
+	index int
+}
+
+func NewPlayback(replay *Replay) *Playback {
+	return &Playback{ replay: replay }
+}
+
+// Returns the next tick, or false if the replay is over.
+func (self *Playback) Next() (Tick, bool) {
+	if self.index >= len(self.replay.Ticks) { return Tick{}, false }
+	tick := self.replay.Ticks[self.index]
+	self.index += 1
+	return tick, true
+}
+
+func (self *Playback) Replay() *Replay { return self.replay }
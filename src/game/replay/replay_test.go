@@ -0,0 +1,63 @@
+package replay
+
+import "slices"
+import "testing"
+
+func TestEncodeDecode(t *testing.T) {
+	replay := &Replay{
+		GameVersion: "v1.2.3",
+		PackHash: 0xDEADBEEF12345678,
+		StartMap: 2,
+		Character: "LUCKY",
+		Seed: -42,
+		ClearTicks: 480,
+	}
+	for i := 0; i < 500; i++ {
+		var buttons Buttons
+		if i % 97 == 0 { buttons |= JumpTrigger }
+		if i % 200 < 30 { buttons |= JumpPressed }
+		replay.Append(NewTick(float64(i/50 % 3) - 1.0, buttons))
+	}
+	replay.Append(NewTick(0.3, DashTrigger | Respawn))
+
+	data := replay.Encode()
+	if len(data) > 200 { t.Fatalf("expected compact encoding, got %d bytes", len(data)) }
+	decoded, err := Decode(data)
+	if err != nil { t.Fatal(err) }
+	if !slices.Equal(decoded.Ticks, replay.Ticks) { t.Fatal("ticks mismatch") }
+	if decoded.GameVersion != replay.GameVersion || decoded.PackHash != replay.PackHash ||
+		decoded.StartMap != replay.StartMap || decoded.Character != replay.Character || decoded.Seed != replay.Seed ||
+		decoded.ClearTicks != replay.ClearTicks {
+		t.Fatalf("header mismatch, expected %+v, got %+v", replay, decoded)
+	}
+
+	// truncated or corrupted data must fail
+	for _, n := range []int{ 0, 3, 10, len(data) - 1 } {
+		_, err := Decode(data[ : n])
+		if err == nil { t.Fatalf("expected error on data truncated at %d", n) }
+	}
+	_, err = Decode(append(data, 0))
+	if err == nil { t.Fatal("expected error on trailing data") }
+}
+
+func TestTickAxis(t *testing.T) {
+	for _, axis := range []float64{ -1.0, -0.5, 0, 0.25, 1.0 } {
+		tick := NewTick(axis, 0)
+		requantized := NewTick(tick.HorzAxis(), 0)
+		if requantized != tick { t.Fatalf("axis %f not stable across quantization", axis) }
+	}
+	if NewTick(2.0, 0).Axis != AxisMax || NewTick(-2.0, 0).Axis != -AxisMax {
+		t.Fatal("expected axis clamping")
+	}
+	if !NewTick(0, JumpPressed | DashTrigger).Has(DashTrigger) { t.Fatal("missing button") }
+}
+
+func TestPlayback(t *testing.T) {
+	replay := &Replay{ Ticks: []Tick{ {Axis: 1}, {Axis: 2} } }
+	playback := NewPlayback(replay)
+	for _, want := range []int8{ 1, 2 } {
+		tick, ok := playback.Next()
+		if !ok || tick.Axis != want { t.Fatalf("expected axis %d, got %d (ok = %t)", want, tick.Axis, ok) }
+	}
+	if _, ok := playback.Next(); ok { t.Fatal("expected end of replay") }
+}
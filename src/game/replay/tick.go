@@ -0,0 +1,39 @@
+package replay
+
+// Gameplay input state for a single tick. Only the inputs that
+// can affect the simulation are included, menus and other UI
+// actions stay outside replays.
+type Tick struct {
+	Axis int8 // horizontal axis, in [-AxisMax, AxisMax]
+	Buttons Buttons
+}
+
+const AxisMax = 127
+
+type Buttons uint8
+const (
+	JumpPressed Buttons = 1 << iota
+	JumpTrigger
+	DashTrigger
+	UseCarrot
+	NextCarrot
+	PrevCarrot
+	Respawn // manual respawn through the menu, applied before the tick
+)
+
+// Returns the tick for the given horizontal axis value in [-1, 1].
+// Axis values are quantized, so live play must also go through
+// ticks in order to stay deterministic.
+func NewTick(horzAxis float64, buttons Buttons) Tick {
+	horzAxis = min(max(horzAxis, -1.0), 1.0)
+	var axis int8
+	if horzAxis >= 0 {
+		axis = int8(float64(horzAxis*AxisMax) + 0.5)
+	} else {
+		axis = int8(float64(horzAxis*AxisMax) - 0.5)
+	}
+	return Tick{ Axis: axis, Buttons: buttons }
+}
+
+func (self Tick) HorzAxis() float64 { return float64(self.Axis)/AxisMax }
+func (self Tick) Has(buttons Buttons) bool { return self.Buttons & buttons == buttons }
@@ -22,6 +22,7 @@ func New(ctx *context.Context) (*BriefBlackout, error) {
 }
 
 func (self *BriefBlackout) Update(ctx *context.Context) (*scene.Change, error) {
+	if ctx.Scenes.Current() != self { return nil, nil } // e.g. gamepad setup on top
 	self.ticksLeft -= 1
 	if self.ticksLeft <= 0 { return scene.Pop(), nil }
 	return nil, nil
@@ -32,6 +32,7 @@ type Editor struct {
 	tileBar TileBar
 	maps []*tile.Map
 	mapIndex int
+	renderCache tile.RenderCache
 	blinker *utils.Blinker
 	menuOptsToRefreshOnMapChange []func()
 	pendingTransition bool
@@ -44,6 +45,7 @@ const (
 	keyTransfers    menu.Key = menu.FirstKey + 3
 	keySetSpawn     menu.Key = menu.FirstKey + 4
 	keySetTransfers menu.Key = menu.FirstKey + 5
+	keyMapRules     menu.Key = menu.FirstKey + 6
 )
 
 var menuTitles = []string{
@@ -100,8 +102,21 @@ func New(ctx *context.Context) (*Editor, error) {
 	opts.Add(&menu.NavOption{ Label: "SET SPAWN", To: keySetSpawn })
 	opts.Add(&menu.NavOption{ Label: "SET TRANSFERS", To: keySetTransfers })
 	opts.Add(&JumpToOption{ Editor: editor })
+	opts.Add(&menu.NavOption{ Label: "MAP RULES", To: keyMapRules })
 	opts.AddBackOption(&menu.NavOption{ Label: "BACK", To: keyMainMenu })
 
+	opts = mainMenu.NewOptionList(keyMapRules)
+	opts.Add(&PhysicsPresetOption{ Editor: editor })
+	opts.Add(&MapRuleOption{
+		Label: "WALL JUMPS",
+		Rule: func() *bool { return &editor.maps[editor.mapIndex].Props.WallJumps },
+	})
+	opts.Add(&MapRuleOption{
+		Label: "AIR DASH",
+		Rule: func() *bool { return &editor.maps[editor.mapIndex].Props.AirDash },
+	})
+	opts.AddBackOption(&menu.NavOption{ Label: "BACK", To: keyTransfers })
+
 	opts = mainMenu.NewOptionList(keySetSpawn)
 	opt := &TileOption{
 		Label: "SPAWN COLUMN",
@@ -194,6 +209,16 @@ func (self *Editor) mapsToString() (string, error) {
 	return strings.Join(strs, "."), nil
 }
 
+// Picks up the physics overrides saved from the tuning menu while
+// playtesting.
+func (self *Editor) applyPlaytestOverrides(ctx *context.Context) {
+	for id, overrides := range ctx.State.PlaytestOverrides {
+		index := int(id) - 1
+		if index >= 0 && index < len(self.maps) { self.maps[index].Props.Physics = overrides }
+	}
+	clear(ctx.State.PlaytestOverrides)
+}
+
 func (self *Editor) mapChangeRefresh() {
 	for i, _ := range self.menuOptsToRefreshOnMapChange {
 		self.menuOptsToRefreshOnMapChange[i]()
@@ -211,6 +236,7 @@ func (self *Editor) notifyMapStartRowChange(ctx *context.Context, newStartRow in
 func (self *Editor) Update(ctx *context.Context) (*scene.Change, error) {
 	ctx.State.Editing = (ctx.Scenes.Current() == self)
 	if !ctx.State.Editing { return nil, nil }
+	self.applyPlaytestOverrides(ctx)
 	if self.pendingTransition {
 		self.pendingTransition = false
 		return scene.PushTo(keys.BriefBlackout), nil
@@ -234,6 +260,7 @@ func (self *Editor) Update(ctx *context.Context) (*scene.Change, error) {
 			ctx.Audio.PlaySFX(au.SfxConfirm)
 			self.menu.JumpTo(keyMainMenu)
 			self.menuActive = !self.menuActive
+			ctx.SaveSettingsIfDirty()
 			self.menu.Title = menuTitles[rand.Intn(len(menuTitles))]
 		}
 
@@ -266,6 +293,7 @@ func (self *Editor) updateEditor(ctx *context.Context) error {
 		ctx.Audio.PlaySFX(au.SfxBack)
 		col, row := self.tileX/20, self.tileY/20
 		self.maps[self.mapIndex].DeleteTile(row, col, self.tileBar.CurrentLayer())
+		self.renderCache.Invalidate()
 	} else if ctx.Input.Trigger(in.ActionConfirm) {
 		ctx.Audio.PlaySFX(au.SfxClick)
 		col, row := self.tileX/20, self.tileY/20
@@ -273,6 +301,7 @@ func (self *Editor) updateEditor(ctx *context.Context) error {
 		tile.Column = uint8(col)
 		tile.Row = uint8(row)
 		self.maps[self.mapIndex].SetTile(tile, self.tileBar.CurrentLayer())
+		self.renderCache.Invalidate()
 	}
 	
 	return nil
@@ -318,9 +347,10 @@ func (self *Editor) DrawLogical(canvas *ebiten.Image, foremost bool, ctx *contex
 
 func (self *Editor) drawEditor(canvas *ebiten.Image, ctx *context.Context) {
 	// draw tiles
-	self.maps[self.mapIndex].DrawBackLogical(canvas, ctx, nil)
-	self.maps[self.mapIndex].DrawMainLogical(canvas, ctx, nil)
-	self.maps[self.mapIndex].DrawFrontLogical(canvas, ctx, nil)
+	tilemap := self.maps[self.mapIndex]
+	self.renderCache.DrawBackLogical(canvas, ctx, tilemap, nil)
+	self.renderCache.DrawMainLogical(canvas, ctx, tilemap, nil)
+	self.renderCache.DrawFrontLogical(canvas, ctx, tilemap, nil)
 	
 	// draw tile bar
 	self.tileBar.DrawLogical(canvas, ctx)
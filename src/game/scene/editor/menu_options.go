@@ -9,6 +9,7 @@ import "github.com/tinne26/luckyfeet/src/game/context"
 import "github.com/tinne26/luckyfeet/src/game/material/in"
 import "github.com/tinne26/luckyfeet/src/game/material/au"
 import "github.com/tinne26/luckyfeet/src/game/components/menu"
+import "github.com/tinne26/luckyfeet/src/game/player/physics"
 
 // extra option types for unique menus
 
@@ -147,3 +148,62 @@ func (self *TransferOption) HoverUpdate(ctx *context.Context) {
 func (self *TransferOption) Confirm(ctx *context.Context) (menu.Key, *scene.Change, error) {	
 	return menu.NoConfirm, nil, nil
 }
+
+// --- "physics preset" option ---
+
+type PhysicsPresetOption struct {
+	Editor *Editor
+}
+func (self *PhysicsPresetOption) Name() string {
+	props := &self.Editor.maps[self.Editor.mapIndex].Props
+	name := physics.PresetName(props.PhysicsPreset)
+	if !props.Physics.IsZero() { name += "+" } // tuned while playtesting
+	return "PHYSICS " + string(text.TriangleLeftWithPad) + name + string(text.TriangleRightWithPad)
+}
+func (self *PhysicsPresetOption) MaxName() string {
+	return "PHYSICS " + string(text.TriangleLeftWithPad) + "CLASSIC+" + string(text.TriangleRightWithPad)
+}
+func (self *PhysicsPresetOption) SoftHighlight(ctx *context.Context) bool { return false }
+func (self *PhysicsPresetOption) HoverUpdate(ctx *context.Context) {
+	dir := ctx.Input.RepeatDirAs(in.RFDefault, in.RNDefault)
+	if dir == in.DirNone { return }
+
+	props := &self.Editor.maps[self.Editor.mapIndex].Props
+	if dir == in.DirRight {
+		props.PhysicsPreset = (props.PhysicsPreset + 1) % physics.PresetCountSentinel
+	} else if dir == in.DirLeft {
+		props.PhysicsPreset = (props.PhysicsPreset + physics.PresetCountSentinel - 1) % physics.PresetCountSentinel
+	}
+	ctx.Audio.PlaySFX(au.SfxClick)
+}
+func (self *PhysicsPresetOption) Confirm(ctx *context.Context) (menu.Key, *scene.Change, error) {
+	return menu.NoConfirm, nil, nil
+}
+
+// --- on/off map rule option ---
+
+type MapRuleOption struct {
+	Label string
+	Rule func() *bool // fetched each time, as the current map can change
+}
+func (self *MapRuleOption) Name() string {
+	value := "OFF"
+	if *self.Rule() { value = "ON" }
+	return self.Label + " " + string(text.TriangleLeftWithPad) + value + string(text.TriangleRightWithPad)
+}
+func (self *MapRuleOption) MaxName() string {
+	return self.Label + " " + string(text.TriangleLeftWithPad) + "OFF" + string(text.TriangleRightWithPad)
+}
+func (self *MapRuleOption) SoftHighlight(ctx *context.Context) bool { return false }
+func (self *MapRuleOption) HoverUpdate(ctx *context.Context) {
+	dir := ctx.Input.RepeatDirAs(in.RFDefault, in.RNDefault).Horz()
+	if dir == in.DirNone { return }
+	rule := self.Rule()
+	*rule = !*rule
+	ctx.Audio.PlaySFX(au.SfxClick)
+}
+func (self *MapRuleOption) Confirm(ctx *context.Context) (menu.Key, *scene.Change, error) {
+	rule := self.Rule()
+	*rule = !*rule
+	return menu.NoChange, nil, nil
+}
@@ -21,6 +21,7 @@ var tileGroups = [][]uint8{
 	{tcsts.MainGround, tcsts.MainGroundRaiser, tcsts.MainGroundSide, tcsts.MainGroundCorner, tcsts.MainSinglePlatform}, // main layer ground
 	{tcsts.MainGroundMark, tcsts.MainGroundMarkCorner}, // main layer ground marks
 	{tcsts.MainGrassSide, tcsts.MainGrassSideFull, tcsts.MainGrassCorner, tcsts.MainGrassCornerFull}, // main layer grass
+	{tcsts.MainSlope45, tcsts.MainSlope22Low, tcsts.MainSlope22High}, // main layer slopes
 	{ // main layer plats
 		tcsts.MainOrangePlatSingle, tcsts.MainOrangePlatLeft, tcsts.MainOrangePlatRight,
 		tcsts.MainYellowPlatSingle, tcsts.MainYellowPlatLeft, tcsts.MainYellowPlatRight,
@@ -29,6 +30,7 @@ var tileGroups = [][]uint8{
 	{tcsts.FrontGround, tcsts.FrontGroundRaiser, tcsts.FrontGroundSide, tcsts.FrontGroundCorner, tcsts.FrontSinglePlatform}, // front layer ground
 	{tcsts.FrontGroundMark, tcsts.FrontGroundMarkCorner}, // front layer ground marks
 	{tcsts.FrontGrassSide, tcsts.FrontGrassSideFull, tcsts.FrontGrassCorner, tcsts.FrontGrassCornerFull}, // front layer grass
+	{tcsts.FrontSlope45, tcsts.FrontSlope22Low, tcsts.FrontSlope22High}, // front layer slopes
 	{tcsts.CarrotOrange, tcsts.CarrotYellow, tcsts.CarrotPurple, tcsts.RaceGoal }, // carrots
 	{ // transfers
 		tcsts.TransferRightA, tcsts.TransferRightB, tcsts.TransferRightC,
@@ -36,6 +38,8 @@ var tileGroups = [][]uint8{
 		tcsts.TransferUpA, tcsts.TransferUpB, tcsts.TransferUpC, 
 		tcsts.TransferDownA, tcsts.TransferDownB, tcsts.TransferDownC, 
 	},
+	{tcsts.ZoneWindRight, tcsts.ZoneWindLeft, tcsts.ZoneUpdraft}, // wind zones
+	{tcsts.EntityPatroller, tcsts.EntityHopper}, // critters
 }
 var tileGroupLayers = []int{
 	tcsts.LayerBack,
@@ -44,11 +48,15 @@ var tileGroupLayers = []int{
 	tcsts.LayerMainDecor,
 	tcsts.LayerMain,
 	tcsts.LayerMain,
+	tcsts.LayerMain,
 	tcsts.LayerFront,
 	tcsts.LayerFrontDecor,
 	tcsts.LayerFront,
+	tcsts.LayerFront,
 	tcsts.LayerSpecial,
 	tcsts.LayerSpecial,
+	tcsts.LayerZone,
+	tcsts.LayerEntities,
 }
 
 type TileBar struct {
@@ -0,0 +1,142 @@
+package gamepadsetup
+
+import "fmt"
+import "image/color"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+import "github.com/tinne26/luckyfeet/src/lib/input"
+import "github.com/tinne26/luckyfeet/src/lib/scene"
+import "github.com/tinne26/luckyfeet/src/lib/text"
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/material/in"
+import "github.com/tinne26/luckyfeet/src/game/material/au"
+import "github.com/tinne26/luckyfeet/src/game/utils"
+
+var _ scene.Scene[*context.Context] = (*GamepadSetup)(nil)
+
+// Asks for each standard gamepad input in turn to build a layout
+// for the active gamepad. Pushed automatically when a gamepad
+// without any usable layout connects, but also reachable from the
+// controls menus. Gamepad input is unreliable here by definition,
+// so skipping and cancelling also work from the keyboard.
+type GamepadSetup struct {
+	calibrator *input.GamepadCalibrator // nil if no gamepad is connected
+}
+
+func New(ctx *context.Context) (*GamepadSetup, error) {
+	setup := &GamepadSetup{}
+	setup.restart(ctx)
+	return setup, nil
+}
+
+func (self *GamepadSetup) restart(ctx *context.Context) {
+	self.calibrator = nil
+	id, found := ctx.Input.Gamepad().ActiveID()
+	if !found { return }
+	calibrator, err := input.NewGamepadCalibrator(id)
+	if err != nil {
+		fmt.Printf("[Gamepad setup not available: %s]\n", err)
+		return
+	}
+	self.calibrator = calibrator
+}
+
+func (self *GamepadSetup) Update(ctx *context.Context) (*scene.Change, error) {
+	if ctx.Scenes.Current() != self { return nil, nil }
+	ctx.Background.Update()
+
+	keyboard := ctx.Input.Keyboard()
+	if keyboard.Trigger(in.ActionBack) {
+		ctx.Audio.PlaySFX(au.SfxBack)
+		ctx.Input.Unwind()
+		return scene.Pop(), nil
+	}
+
+	// start over if the gamepad changes
+	gamepad := ctx.Input.Gamepad()
+	status := gamepad.Status()
+	if status.IsDisconnected() {
+		self.calibrator = nil
+	} else if status.IsJustConnected() {
+		self.restart(ctx)
+	}
+	if self.calibrator == nil {
+		if keyboard.Trigger(in.ActionConfirm) {
+			ctx.Audio.PlaySFX(au.SfxBack)
+			ctx.Input.Unwind()
+			return scene.Pop(), nil
+		}
+		return nil, nil
+	}
+
+	// calibrate current input
+	if keyboard.Trigger(in.ActionConfirm) {
+		self.calibrator.Skip()
+		ctx.Audio.PlaySFX(au.SfxBack)
+	} else {
+		switch self.calibrator.Update() {
+		case input.CalibrationAssigned: ctx.Audio.PlaySFX(au.SfxClick)
+		case input.CalibrationSkipped : ctx.Audio.PlaySFX(au.SfxBack)
+		}
+	}
+	if !self.calibrator.Done() { return nil, nil }
+
+	// save layout and go back
+	err := in.SaveGamepadLayout(gamepad, self.calibrator.GUID(), self.calibrator.Layout())
+	if err != nil { fmt.Printf("[Gamepad layout not saved: %s]\n", err) }
+	ctx.Audio.PlaySFX(au.SfxConfirm)
+	ctx.Toasts.Push("GAMEPAD CONFIGURED")
+	ctx.Input.Unwind()
+	return scene.Pop(), nil
+}
+
+func (self *GamepadSetup) DrawLogical(canvas *ebiten.Image, foremost bool, ctx *context.Context) {
+	if !foremost { return }
+
+	white  := color.RGBA{244, 244, 244, 244}
+	orange := color.RGBA{255, 71, 20, 255}
+	ctx.Background.DrawLogical(canvas, ctx)
+	utils.FillOver(canvas, color.RGBA{0, 0, 0, 180})
+
+	bounds := canvas.Bounds()
+	x := bounds.Dx()/2
+	text.CenterDrawAt(canvas, x, 60, []string{ "GAMEPAD SETUP" }, white, 3)
+
+	kbConfig := ctx.Input.Keyboard().Config()
+	confirmKeys := in.KeyboardTriggerName(kbConfig.GetMapping(in.ActionConfirm))
+	backKeys := in.KeyboardTriggerName(kbConfig.GetMapping(in.ActionBack))
+	if self.calibrator == nil {
+		text.CenterDrawAt(canvas, x, 170, []string{ "NO GAMEPAD CONNECTED" }, orange, 2)
+		hint := "KEYBOARD " + backKeys + ": BACK"
+		text.CenterDrawAt(canvas, x, 300, []string{ hint }, white, 1)
+		return
+	}
+
+	current := self.calibrator.Current()
+	step := fmt.Sprintf("STEP %d/%d", int(current) + 1, input.GamepadNumStandardInputs)
+	text.CenterDrawAt(canvas, x, 120, []string{ step }, white, 2)
+	text.CenterDrawAt(canvas, x, 170, []string{ inputPrompt(current) }, orange, 3)
+	text.CenterDrawAt(canvas, x, 290, []string{
+		"PRESS AN INPUT THAT WAS ALREADY SET TO SKIP",
+		"KEYBOARD " + confirmKeys + ": SKIP, " + backKeys + ": CANCEL",
+	}, white, 1)
+}
+
+func (self *GamepadSetup) DrawHiRes(canvas *ebiten.Image, foremost bool, ctx *context.Context) {
+	// ...
+}
+
+func inputPrompt(stdInput input.GamepadStandardInput) string {
+	switch stdInput {
+	case input.GamepadLeftStickHorzAxis : return "MOVE LEFT STICK RIGHT"
+	case input.GamepadLeftStickVertAxis : return "MOVE LEFT STICK DOWN"
+	case input.GamepadRightStickHorzAxis: return "MOVE RIGHT STICK RIGHT"
+	case input.GamepadRightStickVertAxis: return "MOVE RIGHT STICK DOWN"
+	case input.GamepadLeftStickButton   : return "PRESS LEFT STICK"
+	case input.GamepadRightStickButton  : return "PRESS RIGHT STICK"
+	default:
+		return "PRESS " + in.ButtonName(stdInput)
+	}
+}
@@ -0,0 +1,52 @@
+package gamepadsetup
+
+import "testing"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+import "github.com/tinne26/luckyfeet/src/lib/input"
+import "github.com/tinne26/luckyfeet/src/lib/scene"
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/components/back"
+import "github.com/tinne26/luckyfeet/src/game/material/scene/keys"
+import "github.com/tinne26/luckyfeet/src/game/scene/briefblackout"
+
+// stands in for the scene that pushed the blackout
+type baseScene struct{}
+func (baseScene) Update(*context.Context) (*scene.Change, error) { return nil, nil }
+func (baseScene) DrawLogical(*ebiten.Image, bool, *context.Context) {}
+func (baseScene) DrawHiRes(*ebiten.Image, bool, *context.Context) {}
+
+func TestSetupOverBlackout(t *testing.T) {
+	registry := scene.NewRegistry[*context.Context]()
+	registry.Register(func(*context.Context) scene.Scene[*context.Context] { return baseScene{} }, keys.Start)
+	registry.Register(briefblackout.RequestHook, briefblackout.SceneKey())
+	registry.Register(RequestHook, SceneKey())
+	ctx := &context.Context{
+		Input: input.NewKBGP(),
+		Scenes: scene.NewManager(registry),
+		Background: back.New(),
+	}
+
+	ctx.Scenes.FirstLoad(keys.Start, ctx)
+	ctx.Scenes.Push(keys.BriefBlackout, ctx)
+	ctx.Scenes.Push(keys.GamepadSetup, ctx) // as game.Update does on connection
+	for i := 0; i < 120; i++ {
+		err := ctx.Scenes.Update(ctx) // would panic if the blackout tried to pop
+		if err != nil { t.Fatal(err) }
+	}
+	if _, isSetup := ctx.Scenes.Current().(*GamepadSetup); !isSetup {
+		t.Fatalf("expected gamepad setup to remain on top")
+	}
+
+	// once the setup is gone, the blackout resumes and pops itself
+	ctx.Scenes.Scenes = ctx.Scenes.Scenes[ : 2]
+	for i := 0; i < 120; i++ {
+		err := ctx.Scenes.Update(ctx)
+		if err != nil { t.Fatal(err) }
+	}
+	if _, isBase := ctx.Scenes.Current().(baseScene); !isBase {
+		t.Fatalf("expected the blackout to be popped")
+	}
+}
@@ -0,0 +1,13 @@
+package gamepadsetup
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/material/scene/keys"
+import "github.com/tinne26/luckyfeet/src/lib/scene"
+
+func SceneKey() scene.Key { return keys.GamepadSetup }
+func RequestHook(ctx *context.Context) scene.Scene[*context.Context] {
+	ctx.Input.Unwind()
+	setup, err := New(ctx)
+	if err != nil { panic(err) }
+	return setup
+}
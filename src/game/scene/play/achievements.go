@@ -0,0 +1,28 @@
+package play
+
+import "fmt"
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/achievements"
+
+// Unlocks any achievements reached during the last race step.
+// Must be called before finishStats, as the lifetime stats can't
+// include the current run yet. Replays don't count.
+func (self *Play) checkAchievements(ctx *context.Context, cleared bool) {
+	if self.playback != nil { return }
+	progress := achievements.Progress{
+		Run: self.race.Stats(),
+		Lifetime: ctx.State.Stats,
+		PackHash: self.packHash,
+		StartMap: self.startMap,
+		Cleared: cleared && !self.race.Tuned(),
+		ClearTicks: self.race.Ticks(),
+	}
+	unlocked := ctx.State.Achievements.Check(&progress)
+	if len(unlocked) == 0 { return }
+	for _, achievement := range unlocked {
+		ctx.Toasts.Push("ACHIEVEMENT: " + achievement.Name)
+	}
+	err := ctx.State.Achievements.Save()
+	if err != nil { fmt.Printf("[Achievements not saved: %s]\n", err) }
+}
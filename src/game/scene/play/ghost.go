@@ -0,0 +1,55 @@
+package play
+
+import "fmt"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+import "github.com/tinne26/luckyfeet/src/game/player"
+import "github.com/tinne26/luckyfeet/src/game/ghost"
+import "github.com/tinne26/luckyfeet/src/game/material/characters"
+
+const ghostAlpha = 0.36
+
+// Sets up the ghost of the best run for the level, if any, and
+// starts recording the current run. Ghosts are kept per start
+// map, like records, as they only make sense when starting on
+// the same map as the best run.
+func (self *Play) initGhosts(ctx *context.Context, watching bool) {
+	best := ctx.State.Ghosts[self.recordsKey()]
+	if best != nil && best.Ticks() > 0 && int(best.Poses[0].MapIndex) == self.race.MapIndex() {
+		index := characters.IndexOf(ctx.Characters, best.Character)
+		if index != -1 {
+			self.ghostRun = best
+			self.ghostCharacter = ctx.Characters[index]
+			self.ghostOpts.ColorScale.ScaleAlpha(ghostAlpha)
+		}
+	}
+	if !watching {
+		self.currentRun = &ghost.Run{ Character: self.player.Character().Name }
+	}
+}
+
+// Keeps the current run as the level's best if it's faster, and
+// saves it so the ghost is still there in later sessions.
+func (self *Play) finishGhostRun(ctx *context.Context) {
+	if self.currentRun == nil || self.race.Tuned() { return }
+	key := self.recordsKey()
+	if self.currentRun.BeatsRun(ctx.State.Ghosts[key]) {
+		ctx.State.Ghosts[key] = self.currentRun
+		err := ghost.Save(ctx.State.Ghosts)
+		if err != nil { fmt.Printf("[Ghosts not saved: %s]\n", err) }
+	}
+	self.currentRun = nil
+}
+
+// Draws the ghost if it's on the current map and on the given
+// side of the main layer.
+func (self *Play) drawGhost(canvas *ebiten.Image, ctx *context.Context, behindMain bool) {
+	if self.ghostRun == nil || ctx.Settings.HideGhost { return }
+	pose, found := self.ghostRun.PoseAt(self.race.Ticks() - 1)
+	if !found || int(pose.MapIndex) != self.race.MapIndex() { return }
+	if (int(pose.Layer) == tcsts.LayerBack) != behindMain { return }
+	player.DrawPose(canvas, self.ghostCharacter, pose, &self.ghostOpts)
+}
@@ -0,0 +1,17 @@
+package play
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/leaderboard"
+
+const leaderboardTopEntries = 8
+
+// Submits the last clear to the leaderboard in the background, if
+// enabled. Must be called after finishRecording(). Replays and runs
+// with tuned physics are not submitted.
+func (self *Play) submitToLeaderboard(ctx *context.Context) {
+	ctx.State.Submission = nil
+	if ctx.State.Leaderboard == nil || self.playback != nil || self.race.Tuned() { return }
+	run := ctx.State.Replay
+	if run == nil || run.ClearTicks == 0 { return }
+	ctx.State.Submission = leaderboard.SubmitAsync(ctx.State.Leaderboard, ctx.State.PlayerName, run, leaderboardTopEntries)
+}
@@ -0,0 +1,78 @@
+package play
+
+import "strconv"
+
+import "github.com/tinne26/luckyfeet/src/lib/scene"
+import "github.com/tinne26/luckyfeet/src/lib/text"
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/material/in"
+import "github.com/tinne26/luckyfeet/src/game/material/au"
+import "github.com/tinne26/luckyfeet/src/game/components/menu"
+
+// --- physics tuning option (for playtesting) ---
+
+type TuningOption struct {
+	Label string
+	Value *float64
+	Step float64
+	NotifyChange func()
+}
+func (self *TuningOption) Name() string {
+	value := strconv.FormatFloat(*self.Value, 'f', 3, 64)
+	return self.Label + " " + string(text.TriangleLeftWithPad) + value + string(text.TriangleRightWithPad)
+}
+func (self *TuningOption) MaxName() string {
+	return self.Label + " " + string(text.TriangleLeftWithPad) + "0.000" + string(text.TriangleRightWithPad)
+}
+func (self *TuningOption) SoftHighlight(ctx *context.Context) bool { return false }
+func (self *TuningOption) HoverUpdate(ctx *context.Context) {
+	dir := ctx.Input.RepeatDirAs(in.RFDefault, in.RNDefault).Horz()
+	if dir == in.DirNone { return }
+
+	value := self.Value
+	if dir == in.DirRight {
+		*value += self.Step
+	} else if *value <= 0 {
+		ctx.Audio.PlaySFX(au.SfxScratch)
+		return
+	} else {
+		*value = max(*value - self.Step, 0)
+	}
+	ctx.Audio.PlaySFX(au.SfxClick)
+	self.NotifyChange()
+}
+func (self *TuningOption) Confirm(ctx *context.Context) (menu.Key, *scene.Change, error) {
+	return menu.NoConfirm, nil, nil
+}
+
+type TicksTuningOption struct {
+	Label string
+	Value *int
+	NotifyChange func()
+}
+func (self *TicksTuningOption) Name() string {
+	return self.Label + " " + string(text.TriangleLeftWithPad) + strconv.Itoa(*self.Value) + string(text.TriangleRightWithPad)
+}
+func (self *TicksTuningOption) MaxName() string {
+	return self.Label + " " + string(text.TriangleLeftWithPad) + "000" + string(text.TriangleRightWithPad)
+}
+func (self *TicksTuningOption) SoftHighlight(ctx *context.Context) bool { return false }
+func (self *TicksTuningOption) HoverUpdate(ctx *context.Context) {
+	dir := ctx.Input.RepeatDirAs(in.RFDefault, in.RNDefault).Horz()
+	if dir == in.DirNone { return }
+
+	if dir == in.DirRight {
+		*self.Value += 1
+	} else if *self.Value <= 0 {
+		ctx.Audio.PlaySFX(au.SfxScratch)
+		return
+	} else {
+		*self.Value -= 1
+	}
+	ctx.Audio.PlaySFX(au.SfxClick)
+	self.NotifyChange()
+}
+func (self *TicksTuningOption) Confirm(ctx *context.Context) (menu.Key, *scene.Change, error) {
+	return menu.NoConfirm, nil, nil
+}
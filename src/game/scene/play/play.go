@@ -1,6 +1,9 @@
 package play
 
+import "time"
+import "errors"
 import "strings"
+import "strconv"
 import "math/rand"
 import "image/color"
 
@@ -15,36 +18,66 @@ import "github.com/tinne26/luckyfeet/src/game/material/scene/keys"
 import "github.com/tinne26/luckyfeet/src/game/material/level"
 import "github.com/tinne26/luckyfeet/src/game/components/menu"
 import "github.com/tinne26/luckyfeet/src/game/components/info"
+import "github.com/tinne26/luckyfeet/src/game/components/rebind"
 import "github.com/tinne26/luckyfeet/src/game/components/menuhint"
 import "github.com/tinne26/luckyfeet/src/game/components/racetimer"
 import "github.com/tinne26/luckyfeet/src/game/components/tile"
-import "github.com/tinne26/luckyfeet/src/game/components/tile/tcsts"
+import "github.com/tinne26/luckyfeet/src/game/components/zonefx"
 import "github.com/tinne26/luckyfeet/src/game/player"
+import "github.com/tinne26/luckyfeet/src/game/player/physics"
 import "github.com/tinne26/luckyfeet/src/game/utils"
-import "github.com/tinne26/luckyfeet/src/game/carrot"
+import "github.com/tinne26/luckyfeet/src/game/race"
+import "github.com/tinne26/luckyfeet/src/game/replay"
+import "github.com/tinne26/luckyfeet/src/game/settings"
+import "github.com/tinne26/luckyfeet/src/game/ghost"
+import "github.com/tinne26/luckyfeet/src/game/material/characters"
+import "github.com/tinne26/luckyfeet/src/game/material/version"
 
 var _ scene.Scene[*context.Context] = (*Play)(nil)
 
 type Play struct {
+	race *race.Race
 	player *player.Player
 	maps []*tile.Map // map 0 must be nil
-	mapIndex int
+	renderCache tile.RenderCache
 
 	controls *info.Layer
+	rebind *rebind.Layer
 	menuActive bool
 	menu menu.Menu
-	carrots carrot.Inventory
+	zoneParticles zonefx.Particles
+	
+	rng *rand.Rand // seeded, for anything random during play
+	recording *replay.Replay // nil while watching a replay
+	playback *replay.Playback // nil unless watching a replay
+	pendingRespawn bool // manual respawn, recorded with the next tick
+	packHash uint64
+	startMap uint8
+	raceSplits racetimer.Splits
+	bestRunTimes []int // split times of the personal best, for comparison
+	goldTimes []int // split times if all golds were matched
+
+	ghostRun *ghost.Run // best run to race against, may be nil
+	ghostCharacter *characters.Character
+	ghostOpts ebiten.DrawImageOptions
+	currentRun *ghost.Run // nil while watching a replay
 	
 	smallLightBlinker *utils.Blinker
 	bigLightBlinker *utils.Blinker
 	lightScaleBlinker *utils.Blinker
-	ticksStopwatch int
 	pendingTransition bool
 }
 
 const (
 	keyMainMenu menu.Key = menu.FirstKey
 	keyStopIt   menu.Key = menu.FirstKey + 1
+	keyTuning   menu.Key = menu.FirstKey + 2
+	keyTunePresets menu.Key = menu.FirstKey + 3
+	keyTuneGround  menu.Key = menu.FirstKey + 4
+	keyTuneAir     menu.Key = menu.FirstKey + 5
+	keyTuneAssists menu.Key = menu.FirstKey + 6
+	keyRaceHUD     menu.Key = menu.FirstKey + 7
+	keyControls    menu.Key = menu.FirstKey + 8
 )
 
 var menuTitles = []string{
@@ -60,15 +93,26 @@ var menuTitles = []string{
 
 func New(ctx *context.Context) (*Play, error) {
 	var controls info.Layer
-	play := &Play{ controls: &controls, mapIndex: 0, pendingTransition: true }
-	play.carrots.Initialize()
-	play.player = player.New(ctx)
+	play := &Play{ controls: &controls, rebind: rebind.New(), pendingTransition: true }
+	watching := ctx.State.WatchReplay && ctx.State.Replay != nil
+	ctx.State.WatchReplay = false
+	characterIndex := min(max(ctx.State.CharacterIndex, 0), len(ctx.Characters) - 1)
+	if watching {
+		characterIndex = characters.IndexOf(ctx.Characters, ctx.State.Replay.Character)
+		if characterIndex == -1 { return play, errors.New("replay character not found") }
+	}
 
 	// load maps
 	var mapsData string
-	if ctx.State.PlaytestData != "" {
+	var startMap int
+	if watching {
+		key, found := level.FindPack(ctx.State.Replay.PackHash)
+		if !found { return play, errors.New("replay level pack not found") }
+		mapsData = level.GetData(key)
+		startMap = int(ctx.State.Replay.StartMap)
+	} else if ctx.State.PlaytestData != "" {
 		mapsData = ctx.State.PlaytestData
-		play.mapIndex = int(ctx.State.PlaytestMapID) - 1 // unsafe but let's assume it works
+		startMap = int(ctx.State.PlaytestMapID) - 1 // unsafe but let's assume it works
 	} else if ctx.State.LoadMapDataFromClipboard {
 		ctx.State.LoadMapDataFromClipboard = false
 		mapsData = utils.ReadClipboard()
@@ -83,6 +127,31 @@ func New(ctx *context.Context) (*Play, error) {
 		play.maps[i], err = tile.LoadMapFromString(str)
 		if err != nil { return play, err }
 	}
+	var err error
+	play.race, err = race.New(play.maps, startMap, &ctx.Characters[characterIndex].Descriptor)
+	if err != nil { return play, err }
+	play.player = player.New(ctx, characterIndex, play.race.Body())
+	play.player.Subscribe(player.SoundEffects{})
+	play.packHash = level.PackHash(mapsData)
+	play.startMap = uint8(startMap)
+	play.initSplits(ctx)
+	play.initGhosts(ctx, watching)
+
+	// set up replay recording or playback
+	if watching {
+		play.playback = replay.NewPlayback(ctx.State.Replay)
+		play.rng = rand.New(rand.NewSource(ctx.State.Replay.Seed))
+	} else {
+		seed := time.Now().UnixNano()
+		play.rng = rand.New(rand.NewSource(seed))
+		play.recording = &replay.Replay{
+			GameVersion: version.Game,
+			PackHash: play.packHash,
+			StartMap: play.startMap,
+			Character: play.player.Character().Name,
+			Seed: seed,
+		}
+	}
 
 	// create menu
 	var mainMenu menu.Menu
@@ -96,26 +165,42 @@ func New(ctx *context.Context) (*Play, error) {
 		},
 	})
 	opts.Add(&menu.NavOption{ Label: "OPTIONS", To: menu.Options })
-	opts.Add(controls.NewOption("CONTROLS"))
+	opts.Add(&menu.NavOption{ Label: "CONTROLS", To: keyControls })
+	opts.Add(&menu.NavOption{ Label: "RACE HUD", To: keyRaceHUD })
+	if ctx.State.PlaytestData != "" && !watching {
+		opts.Add(&menu.NavOption{ Label: "TUNING", To: keyTuning })
+	}
 	opts.Add(&menu.NavOption{ Label: "STOP IT", To: keyStopIt })
 	
 	mainMenu.NewGameOptionsOptionList(ctx)
+	newRaceHUDOptionList(&mainMenu)
+	opts = mainMenu.NewOptionList(keyControls)
+	opts.Add(controls.NewOption("VIEW"))
+	opts.Add(play.rebind.NewOption("REBIND"))
+	opts.Add(&menu.SceneChangeOption{ Label: "GAMEPAD SETUP", Change: *scene.PushTo(keys.GamepadSetup) })
+	opts.AddBackOption(&menu.NavOption{ Label: "BACK", To: keyMainMenu })
+	if ctx.State.PlaytestData != "" && !watching {
+		play.newTuningOptionLists(&mainMenu)
+	}
 
 	opts = mainMenu.NewOptionList(keyStopIt)
-	opts.Add(&menu.EffectOption{
-		Label: "RESPAWN",
-		OnConfirm: func(fnCtx *context.Context) error {
-			play.carrots.RemoveAll()
-			play.respawnPlayer(fnCtx)
-			play.menu.JumpTo(keyMainMenu)
-			play.menuActive = false
-			return nil
-		},
-	})
+	if !watching { // manual respawns would desync replays
+		opts.Add(&menu.EffectOption{
+			Label: "RESPAWN",
+			OnConfirm: func(fnCtx *context.Context) error {
+				play.pendingRespawn = true
+				play.menu.JumpTo(keyMainMenu)
+				play.menuActive = false
+				return nil
+			},
+		})
+	}
 	opts.Add(&menu.SceneChangeEffectOption{
 		Label: "EXIT RACE",
 		Change: *scene.Pop(),
 		OnConfirm: func(fnCtx *context.Context) error {
+			play.finishRecording(fnCtx, false)
+			play.finishStats(fnCtx, false)
 			fnCtx.State.PlaytestData = ""
 			return nil
 		},
@@ -133,9 +218,148 @@ func New(ctx *context.Context) (*Play, error) {
 	return play, nil
 }
 
+// Syncs the presentation after the race respawns the player.
 func (self *Play) respawnPlayer(ctx *context.Context) {
-	tilemap := self.maps[self.mapIndex]
-	self.player.Respawn(ctx, tilemap)
+	self.player.Respawn(ctx)
+	self.zoneParticles.SetZones(self.race.Map(), self.rng)
+}
+
+// Timer, splits and ghost options.
+func newRaceHUDOptionList(mainMenu *menu.Menu) {
+	opts := mainMenu.NewOptionList(keyRaceHUD)
+	opts.Add(&menu.EffectOptionWithHighlight{
+		Label: "SPLIT TIMER",
+		OnConfirm: func(ctx *context.Context) error {
+			ctx.Settings.SplitTimer = !ctx.Settings.SplitTimer
+			ctx.SaveSettings()
+			return nil
+		},
+		HighlightFunc: func(ctx *context.Context) bool {
+			return ctx.Settings.SplitTimer
+		},
+	})
+	opts.Add(&menu.EffectOptionWithHighlight{
+		Label: "SPLIT LIST",
+		OnConfirm: func(ctx *context.Context) error {
+			ctx.Settings.ShowSplits = !ctx.Settings.ShowSplits
+			if ctx.Settings.ShowSplits { ctx.Settings.SplitTimer = true }
+			ctx.SaveSettings()
+			return nil
+		},
+		HighlightFunc: func(ctx *context.Context) bool {
+			return ctx.Settings.SplitTimer && ctx.Settings.ShowSplits
+		},
+	})
+	opts.Add(&menu.EffectOptionWithHighlight{
+		Label: "VS BEST RUN",
+		OnConfirm: func(ctx *context.Context) error {
+			ctx.Settings.SplitComparison = settings.CompareBestRun
+			ctx.SaveSettings()
+			return nil
+		},
+		HighlightFunc: func(ctx *context.Context) bool {
+			return ctx.Settings.SplitComparison == settings.CompareBestRun
+		},
+	})
+	opts.Add(&menu.EffectOptionWithHighlight{
+		Label: "VS BEST SEGMENTS",
+		OnConfirm: func(ctx *context.Context) error {
+			ctx.Settings.SplitComparison = settings.CompareBestSegments
+			ctx.SaveSettings()
+			return nil
+		},
+		HighlightFunc: func(ctx *context.Context) bool {
+			return ctx.Settings.SplitComparison == settings.CompareBestSegments
+		},
+	})
+	opts.Add(&menu.EffectOptionWithHighlight{
+		Label: "GHOST",
+		OnConfirm: func(ctx *context.Context) error {
+			ctx.Settings.HideGhost = !ctx.Settings.HideGhost
+			ctx.SaveSettings()
+			return nil
+		},
+		HighlightFunc: func(ctx *context.Context) bool {
+			return !ctx.Settings.HideGhost
+		},
+	})
+	opts.AddBackOption(&menu.NavOption{ Label: "BACK", To: menu.Back })
+}
+
+// Physics tuning menus, only available while playtesting.
+func (self *Play) newTuningOptionLists(mainMenu *menu.Menu) {
+	opts := mainMenu.NewOptionList(keyTuning)
+	opts.Add(&menu.NavOption{ Label: "PRESETS", To: keyTunePresets })
+	opts.Add(&menu.NavOption{ Label: "GROUND AND JUMP", To: keyTuneGround })
+	opts.Add(&menu.NavOption{ Label: "AIR", To: keyTuneAir })
+	opts.Add(&menu.NavOption{ Label: "ASSISTS", To: keyTuneAssists })
+	opts.Add(&menu.EffectOption{
+		Label: "RESET TO MAP",
+		OnConfirm: func(*context.Context) error {
+			self.race.ResetProfile()
+			return nil
+		},
+	})
+	opts.Add(&menu.EffectOption{ Label: "SAVE TO MAP", OnConfirm: self.saveTuningToMap })
+	opts.AddBackOption(&menu.NavOption{ Label: "BACK", To: keyMainMenu })
+
+	opts = mainMenu.NewOptionList(keyTunePresets)
+	for preset := uint8(0); preset < physics.PresetCountSentinel; preset++ {
+		preset := preset // capture for closures (go 1.21)
+		opts.Add(&menu.EffectOptionWithHighlight{
+			Label: physics.PresetName(preset),
+			OnConfirm: func(*context.Context) error {
+				self.race.SetProfile(physics.PresetProfile(preset))
+				return nil
+			},
+			HighlightFunc: func(*context.Context) bool {
+				return *self.race.Profile() == physics.PresetProfile(preset)
+			},
+		})
+	}
+	opts.AddBackOption(&menu.NavOption{ Label: "BACK", To: keyTuning })
+
+	profile := self.race.Profile()
+	notifyChange := func() { self.race.SetTuned() }
+	opts = mainMenu.NewOptionList(keyTuneGround)
+	opts.Add(&TuningOption{ Label: "RUN", Value: &profile.RunSpeed, Step: 0.025, NotifyChange: notifyChange })
+	opts.Add(&TuningOption{ Label: "JUMP", Value: &profile.JumpInitialSpeed, Step: 0.05, NotifyChange: notifyChange })
+	opts.Add(&TuningOption{ Label: "GRAVITY", Value: &profile.DefaultGravity, Step: 0.002, NotifyChange: notifyChange })
+	opts.Add(&TuningOption{ Label: "EXTRA GRAVITY", Value: &profile.ExtraGravity, Step: 0.005, NotifyChange: notifyChange })
+	opts.AddBackOption(&menu.NavOption{ Label: "BACK", To: keyTuning })
+
+	opts = mainMenu.NewOptionList(keyTuneAir)
+	opts.Add(&TuningOption{ Label: "MAX FALL", Value: &profile.MaxFallSpeed, Step: 0.05, NotifyChange: notifyChange })
+	opts.Add(&TuningOption{ Label: "AIR SPEED", Value: &profile.AirExtraHorzSpeed, Step: 0.01, NotifyChange: notifyChange })
+	opts.Add(&TuningOption{ Label: "TIC-TAC SPEED", Value: &profile.TicTacAirHorzSpeedMult, Step: 0.02, NotifyChange: notifyChange })
+	opts.Add(&TuningOption{ Label: "WALL SLIDE", Value: &profile.WallSlideSpeed, Step: 0.02, NotifyChange: notifyChange })
+	opts.Add(&TuningOption{ Label: "WALL PUSH", Value: &profile.WallJumpPushSpeed, Step: 0.05, NotifyChange: notifyChange })
+	opts.AddBackOption(&menu.NavOption{ Label: "BACK", To: keyTuning })
+
+	opts = mainMenu.NewOptionList(keyTuneAssists)
+	opts.Add(&TicksTuningOption{ Label: "COYOTE TICKS", Value: &profile.CoyoteTicks, NotifyChange: notifyChange })
+	opts.Add(&TicksTuningOption{ Label: "JUMP BUFFER", Value: &profile.JumpBufferTicks, NotifyChange: notifyChange })
+	opts.Add(&TicksTuningOption{ Label: "WALL PUSH TICKS", Value: &profile.WallJumpPushTicks, NotifyChange: notifyChange })
+	opts.Add(&TuningOption{ Label: "DASH SPEED", Value: &profile.DashSpeed, Step: 0.1, NotifyChange: notifyChange })
+	opts.Add(&TicksTuningOption{ Label: "DASH TICKS", Value: &profile.DashTicks, NotifyChange: notifyChange })
+	opts.AddBackOption(&menu.NavOption{ Label: "BACK", To: keyTuning })
+}
+
+// Stores the tuned profile as physics overrides on the current map,
+// and leaves them for the editor to pick up once playtesting ends.
+func (self *Play) saveTuningToMap(ctx *context.Context) error {
+	tilemap := self.race.Map()
+	tuned := *self.race.Profile()
+	tilemap.Props.Physics = physics.Overrides{} // so MapProfile() gives the base profile
+	tilemap.Props.Physics = physics.NewOverrides(self.race.MapProfile(), tuned)
+	self.race.ResetProfile() // same profile, but now following the map
+
+	if ctx.State.PlaytestOverrides == nil {
+		ctx.State.PlaytestOverrides = make(map[uint8]physics.Overrides)
+	}
+	ctx.State.PlaytestOverrides[tilemap.ID] = tilemap.Props.Physics
+	ctx.Toasts.Push("TUNING SAVED TO MAP " + strconv.Itoa(int(tilemap.ID)))
+	return nil
 }
 
 func (self *Play) Update(ctx *context.Context) (*scene.Change, error) {
@@ -155,12 +379,15 @@ func (self *Play) Update(ctx *context.Context) (*scene.Change, error) {
 	// update info layer / menu
 	if self.controls.IsVisible() {
 		self.controls.Update(ctx)
+	} else if self.rebind.IsVisible() {
+		self.rebind.Update(ctx)
 	} else {
 		// detect menu opening / closing
 		if ctx.Input.Trigger(in.ActionMenu) || ctx.Input.Trigger(in.ActionMenuBrowserAlt) {
 			ctx.Audio.PlaySFX(au.SfxConfirm)
 			self.menu.JumpTo(keyMainMenu)
 			self.menuActive = !self.menuActive
+			ctx.SaveSettingsIfDirty()
 			self.menu.Title = menuTitles[rand.Intn(len(menuTitles))]
 		}
 
@@ -179,23 +406,41 @@ func (self *Play) Update(ctx *context.Context) (*scene.Change, error) {
 }
 
 func (self *Play) mainUpdate(ctx *context.Context) (*scene.Change, error) {
-	var err error
-
-	self.ticksStopwatch += 1
-
-	err = self.player.Update(ctx, &self.carrots, self.maps[self.mapIndex])
-	if err != nil { return nil, err }
+	tick, found := self.nextTick(ctx)
+	if !found { return scene.Pop(), nil } // replay over
+
+	outcome := self.race.Step(tick)
+	if outcome.Has(race.ManualRespawn) { self.respawnPlayer(ctx) }
+	self.checkAchievements(ctx, outcome.Has(race.Finished))
+	self.player.Update(ctx, self.race.Events())
+	if self.currentRun != nil {
+		self.currentRun.Record(self.player.Pose(self.race.MapIndex()))
+	}
 
-	if self.player.HasFallen() {
+	switch {
+	case outcome.Has(race.Died):
 		ctx.Audio.PlaySFX(au.SfxBack)
-		self.carrots.RemoveAll()
 		self.respawnPlayer(ctx)
-	} else {
-		change, err := self.playerSpecialUpdate(ctx)
-		if change != nil || err != nil { return change, err }
+	case outcome.Has(race.Finished):
+		ctx.State.LastClearTicks = self.race.Ticks()
+		self.submitClear(ctx)
+		self.finishRecording(ctx, true)
+		self.submitToLeaderboard(ctx)
+		self.finishStats(ctx, true)
+		self.finishGhostRun(ctx)
+		ctx.Audio.PlaySFX(au.SfxClick)
+		return scene.ReplaceTo(keys.WinScreen), nil
+	case outcome.Has(race.Transferred):
+		self.respawnPlayer(ctx)
+		ctx.Audio.PlaySFX(au.SfxClick)
+		return scene.PushTo(keys.BriefBlackout), nil
 	}
 
-	self.carrots.Update(ctx)
+	if outcome.Has(race.PickedCarrot) { ctx.Audio.PlaySFX(au.SfxClick) }
+	if outcome.Has(race.SwitchedCarrot) { ctx.Audio.PlaySFX(au.SfxClick) }
+	if outcome.Has(race.ConsumedCarrot) { ctx.Audio.PlaySFX(au.SfxCronch) }
+	if outcome.Has(race.ConsumeFailed) { ctx.Audio.PlaySFX(au.SfxScratch) }
+	self.zoneParticles.Update()
 	self.smallLightBlinker.Update()
 	self.bigLightBlinker.Update()
 	self.lightScaleBlinker.Update()
@@ -203,50 +448,6 @@ func (self *Play) mainUpdate(ctx *context.Context) (*scene.Change, error) {
 	return nil, nil
 }
 
-func (self *Play) playerSpecialUpdate(ctx *context.Context) (*scene.Change, error) {
-	rect := self.player.GetSpecialRect()
-	tilemap := self.maps[self.mapIndex]
-	tile, found := tilemap.GetFirstCollision(ctx, &self.carrots, rect, tcsts.LayerSpecial)
-	if !found { return nil, nil }
-	
-	switch tile.ID {
-	case tcsts.RaceGoal:
-		ctx.State.LastClearTicks = self.ticksStopwatch
-		ctx.Audio.PlaySFX(au.SfxClick)
-		return scene.ReplaceTo(keys.WinScreen), nil
-	case tcsts.CarrotOrange:
-		carr := carrot.Carrot{ Variety: carrot.Orange, OriginCol: tile.Column, OriginRow: tile.Row }
-		if self.carrots.TryAdd(ctx, carr) { ctx.Audio.PlaySFX(au.SfxClick) }
-	case tcsts.CarrotYellow:
-		carr := carrot.Carrot{ Variety: carrot.Yellow, OriginCol: tile.Column, OriginRow: tile.Row }
-		if self.carrots.TryAdd(ctx, carr) { ctx.Audio.PlaySFX(au.SfxClick) }
-	case tcsts.CarrotPurple:
-		carr := carrot.Carrot{ Variety: carrot.Purple, OriginCol: tile.Column, OriginRow: tile.Row }
-		if self.carrots.TryAdd(ctx, carr) { ctx.Audio.PlaySFX(au.SfxClick) }
-	default:
-		if tile.ID >= tcsts.TransferUp {
-			var targetMapID uint8
-			switch tile.ID & 0b11 { // could be shortened with [(tile.ID & 0b11) - 1]
-			case 1: targetMapID = tilemap.TransferIDs[0] // A
-			case 2: targetMapID = tilemap.TransferIDs[1] // B
-			case 3: targetMapID = tilemap.TransferIDs[2] // C
-			default:
-				panic("broken code")
-			}
-			if targetMapID == 0 {
-				panic("map transfer doesn't have a target defined")
-			}
-
-			self.mapIndex = int(targetMapID - 1) // this is not safe, but I have bigger problems in my life
-			self.respawnPlayer(ctx)
-			ctx.Audio.PlaySFX(au.SfxClick)
-			return scene.PushTo(keys.BriefBlackout), nil
-		}
-	}
-
-	return nil, nil
-}
-
 func (self *Play) DrawLogical(canvas *ebiten.Image, foremost bool, ctx *context.Context) {
 	if !foremost { return }
 
@@ -263,6 +464,8 @@ func (self *Play) DrawLogical(canvas *ebiten.Image, foremost bool, ctx *context.
 			self.controls.SetContent(info.ControlsKB)
 		}
 		self.controls.Draw(canvas)
+	} else if self.rebind.IsVisible() {
+		self.rebind.Draw(canvas, ctx)
 	} else if self.menuActive {
 		utils.FillOver(canvas, color.RGBA{0, 0, 0, 48})
 		self.menu.DrawLogical(canvas, ctx)
@@ -299,17 +502,28 @@ func (self *Play) mainDraw(canvas *ebiten.Image, ctx *context.Context) {
 	canvas.DrawImage(ctx.Gfxcore.BackLightingSmall, &opts)
 	
 	// draw map and player
-	self.maps[self.mapIndex].DrawBackLogical(canvas, ctx, &self.carrots)
+	tilemap, carrots := self.race.Map(), self.race.Carrots()
+	self.renderCache.DrawBackLogical(canvas, ctx, tilemap, carrots)
+	self.zoneParticles.DrawLogical(canvas, ctx)
+	self.drawGhost(canvas, ctx, true)
 	if self.player.BehindMain() { self.player.Draw(canvas, ctx) }
-	self.maps[self.mapIndex].DrawMainLogical(canvas, ctx, &self.carrots)
+	self.renderCache.DrawMainLogical(canvas, ctx, tilemap, carrots)
+	self.race.Actors().DrawLogical(canvas, ctx)
+	self.drawGhost(canvas, ctx, false)
 	if self.player.InFrontMain() { self.player.Draw(canvas, ctx) }
-	self.maps[self.mapIndex].DrawFrontLogical(canvas, ctx, &self.carrots)
+	self.renderCache.DrawFrontLogical(canvas, ctx, tilemap, carrots)
 
 	// draw timer
-	racetimer.Draw(canvas, ctx, self.ticksStopwatch)
+	var label string
+	if self.playback != nil { label = "REPLAY" }
+	if ctx.Settings.SplitTimer {
+		self.drawSplits(canvas, ctx, label)
+	} else {
+		racetimer.DrawWithLabel(canvas, ctx, self.race.Ticks(), label)
+	}
 
 	// draw carrots inventory
-	self.carrots.Draw(canvas, ctx)
+	carrots.Draw(canvas, ctx)
 }
 
 func (self *Play) DrawHiRes(canvas *ebiten.Image, foremost bool, ctx *context.Context) {
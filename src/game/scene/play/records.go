@@ -0,0 +1,53 @@
+package play
+
+import "fmt"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/records"
+import "github.com/tinne26/luckyfeet/src/game/settings"
+import "github.com/tinne26/luckyfeet/src/game/components/racetimer"
+
+// Sets up the split comparisons from the personal best, if any.
+func (self *Play) initSplits(ctx *context.Context) {
+	best := ctx.State.Records.Best(self.recordsKey())
+	if best == nil { return }
+	self.bestRunTimes = best.SplitTimes()
+	self.goldTimes = best.GoldTimes()
+	self.raceSplits.Golds = append([]int(nil), best.Golds...)
+}
+
+func (self *Play) recordsKey() records.Key {
+	return records.Key{ PackHash: self.packHash, StartMap: self.startMap }
+}
+
+// Registers the clear on the personal bests and leaves the
+// comparison data on the state for the win screen. Replays and
+// runs with tuned physics don't count.
+func (self *Play) submitClear(ctx *context.Context) {
+	ctx.State.LastBestTicks = 0
+	ctx.State.LastClearIsPB = false
+	if self.playback != nil || self.race.Tuned() { return }
+
+	key := self.recordsKey()
+	best := ctx.State.Records.Best(key)
+	if best != nil { ctx.State.LastBestTicks = best.ClearTicks }
+	isPB := ctx.State.Records.Submit(key, self.race.Ticks(), self.race.Splits())
+	ctx.State.LastClearIsPB = isPB
+	err := ctx.State.Records.Save() // golds may have changed even without a PB
+	if err != nil { fmt.Printf("[Personal bests not saved: %s]\n", err) }
+}
+
+func (self *Play) drawSplits(canvas *ebiten.Image, ctx *context.Context, label string) {
+	self.raceSplits.Times = self.race.Splits()
+	switch ctx.Settings.SplitComparison {
+	case settings.CompareBestRun:
+		self.raceSplits.Comparison = self.bestRunTimes
+	case settings.CompareBestSegments:
+		self.raceSplits.Comparison = self.goldTimes
+	default:
+		panic("broken code")
+	}
+	racetimer.DrawSplits(canvas, ctx, self.race.Ticks(), label, &self.raceSplits, ctx.Settings.ShowSplits)
+}
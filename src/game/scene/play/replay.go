@@ -0,0 +1,59 @@
+package play
+
+import "fmt"
+
+import "github.com/tinne26/luckyfeet/src/lib/input"
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+import "github.com/tinne26/luckyfeet/src/game/material/in"
+import "github.com/tinne26/luckyfeet/src/game/replay"
+
+// Returns the gameplay input for the current tick, either read
+// from the replay being watched or captured from live input and
+// recorded. Returns false when the watched replay is over.
+func (self *Play) nextTick(ctx *context.Context) (replay.Tick, bool) {
+	if self.playback != nil { return self.playback.Next() }
+
+	tick := captureTick(ctx.Input)
+	if self.pendingRespawn {
+		tick.Buttons |= replay.Respawn
+		self.pendingRespawn = false
+	}
+	if self.recording != nil && len(self.recording.Ticks) < replay.MaxTicks {
+		self.recording.Append(tick)
+	}
+	return tick, true
+}
+
+func captureTick(kbgp *input.KBGP) replay.Tick {
+	var buttons replay.Buttons
+	if kbgp.Pressed(in.ActionJump) { buttons |= replay.JumpPressed }
+	if kbgp.Trigger(in.ActionJump) { buttons |= replay.JumpTrigger }
+	if kbgp.Trigger(in.ActionDash) { buttons |= replay.DashTrigger }
+	if kbgp.Trigger(in.ActionUseCarrot ) { buttons |= replay.UseCarrot  }
+	if kbgp.Trigger(in.ActionNextCarrot) { buttons |= replay.NextCarrot }
+	if kbgp.Trigger(in.ActionPrevCarrot) { buttons |= replay.PrevCarrot }
+	return replay.NewTick(kbgp.HorzAxis(), buttons)
+}
+
+// Keeps the recorded run as the last replay and tries to save it
+// to a file. Runs with tuned physics can't be reproduced, so they
+// are discarded. Finished runs also store their clear time, so
+// replay verification can detect desyncs.
+func (self *Play) finishRecording(ctx *context.Context, finished bool) {
+	if self.recording == nil { return }
+	recording := self.recording
+	self.recording = nil
+	if self.race.Tuned() { return }
+	if finished && len(recording.Ticks) == self.race.Ticks() {
+		recording.ClearTicks = self.race.Ticks()
+	}
+
+	ctx.State.Replay = recording
+	path, err := replay.SaveFile(recording)
+	if err != nil {
+		fmt.Printf("[Replay not saved: %s]\n", err)
+	} else {
+		fmt.Printf("[Replay saved to %s]\n", path)
+	}
+}
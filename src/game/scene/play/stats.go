@@ -0,0 +1,16 @@
+package play
+
+import "fmt"
+
+import "github.com/tinne26/luckyfeet/src/game/context"
+
+// Adds the run stats to the lifetime totals and keeps them for the
+// win screen. Replays don't count, as they were already counted
+// when recorded.
+func (self *Play) finishStats(ctx *context.Context, cleared bool) {
+	if self.playback != nil { return }
+	ctx.State.LastRunStats = self.race.Stats()
+	ctx.State.Stats.Add(self.race.Stats(), self.packHash, cleared)
+	err := ctx.State.Stats.Save()
+	if err != nil { fmt.Printf("[Stats not saved: %s]\n", err) }
+}
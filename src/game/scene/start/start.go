@@ -8,11 +8,13 @@ import "github.com/tinne26/luckyfeet/src/lib/scene"
 import "github.com/tinne26/luckyfeet/src/lib/text"
 
 import "github.com/tinne26/luckyfeet/src/game/material/level"
+import "github.com/tinne26/luckyfeet/src/game/material/characters"
 import "github.com/tinne26/luckyfeet/src/game/material/au"
 import "github.com/tinne26/luckyfeet/src/game/context"
 import "github.com/tinne26/luckyfeet/src/game/components/back"
 import "github.com/tinne26/luckyfeet/src/game/components/menu"
 import "github.com/tinne26/luckyfeet/src/game/components/info"
+import "github.com/tinne26/luckyfeet/src/game/components/rebind"
 import "github.com/tinne26/luckyfeet/src/game/components/tile"
 import "github.com/tinne26/luckyfeet/src/game/material/scene/keys"
 import "github.com/tinne26/luckyfeet/src/game/utils"
@@ -22,8 +24,12 @@ var _ scene.Scene[*context.Context] = (*Start)(nil)
 type Start struct {
 	credits *info.Layer
 	controls *info.Layer
+	rebind *rebind.Layer
+	stats *info.Layer
+	achievements *info.Layer
 	menu menu.Menu
 	backMap *tile.Map
+	backMapCache tile.RenderCache
 	codes []rune
 }
 
@@ -32,6 +38,8 @@ const (
 	keyEditor   menu.Key = menu.FirstKey + 1
 	keyWonder   menu.Key = menu.FirstKey + 2
 	keyLvlSel   menu.Key = menu.FirstKey + 3
+	keyCharSel  menu.Key = menu.FirstKey + 4
+	keyControls menu.Key = menu.FirstKey + 5
 )
 
 func New(ctx *context.Context) (*Start, error) {
@@ -48,6 +56,9 @@ func New(ctx *context.Context) (*Start, error) {
 		"GITHUB.COM/TINNE26/LUCKYFEET",
 	})
 	controls := info.New(info.ControlsKB)
+	rebinder := rebind.New()
+	stats := info.New(nil)
+	achievements := info.New(nil)
 	
 	var mainMenu menu.Menu
 	opts := mainMenu.NewOptionList(keyMainMenu)
@@ -71,12 +82,61 @@ func New(ctx *context.Context) (*Start, error) {
 			return nil
 		},
 	})
+	opts.Add(&menu.SceneChangeCheckOption{
+		Label: "WATCH REPLAY",
+		Change: *scene.PushTo(keys.Play),
+		OnConfirm: func(fnCtx *context.Context) bool {
+			if fnCtx.State.Replay == nil { return false }
+			_, found := level.FindPack(fnCtx.State.Replay.PackHash)
+			if !found { return false } // only built-in levels can be replayed
+			if characters.IndexOf(fnCtx.Characters, fnCtx.State.Replay.Character) == -1 { return false }
+			fnCtx.State.WatchReplay = true
+			return true
+		},
+	})
+	opts.Add(&menu.NavOption{ Label: "CHARACTER", To: keyCharSel })
 	opts.AddBackOption(&menu.NavOption{ Label: "BACK", To: keyMainMenu })
+	opts = mainMenu.NewOptionList(keyCharSel)
+	for i, character := range ctx.Characters {
+		i := i // capture for closures (go 1.21)
+		opts.Add(&menu.EffectOptionWithHighlight{
+			Label: character.Name,
+			OnConfirm: func(fnCtx *context.Context) error {
+				fnCtx.State.CharacterIndex = i
+				return nil
+			},
+			HighlightFunc: func(fnCtx *context.Context) bool {
+				return fnCtx.State.CharacterIndex == i
+			},
+		})
+	}
+	opts.AddBackOption(&menu.NavOption{ Label: "BACK", To: keyLvlSel })
 	opts = mainMenu.NewOptionList(keyWonder)
 	opts.Add(&menu.NavOption{ Label: "OPTIONS", To: menu.Options })
-	opts.Add(controls.NewOption("CONTROLS"))
+	opts.Add(&menu.NavOption{ Label: "CONTROLS", To: keyControls })
 	opts.Add(credits.NewOption("CREDITS"))
+	opts.Add(&menu.EffectOption{
+		Label: "STATS",
+		OnConfirm: func(fnCtx *context.Context) error {
+			stats.SetContent(info.LifetimeStats(fnCtx.State.Stats))
+			stats.Show()
+			return nil
+		},
+	})
+	opts.Add(&menu.EffectOption{
+		Label: "ACHIEVEMENTS",
+		OnConfirm: func(fnCtx *context.Context) error {
+			achievements.SetContent(info.Achievements(fnCtx.State.Achievements))
+			achievements.Show()
+			return nil
+		},
+	})
 	opts.AddBackOption(&menu.NavOption{ Label: "BACK", To: keyMainMenu })
+	opts = mainMenu.NewOptionList(keyControls)
+	opts.Add(controls.NewOption("VIEW"))
+	opts.Add(rebinder.NewOption("REBIND"))
+	opts.Add(&menu.SceneChangeOption{ Label: "GAMEPAD SETUP", Change: *scene.PushTo(keys.GamepadSetup) })
+	opts.AddBackOption(&menu.NavOption{ Label: "BACK", To: keyWonder })
 	opts = mainMenu.NewOptionList(keyEditor)
 	opts.Add(&menu.SceneChangeOption{ Label: "NEW PROJECT", Change: *scene.PushTo(keys.Editor) })
 	opts.Add(&menu.SceneChangeEffectOption{
@@ -106,6 +166,9 @@ func New(ctx *context.Context) (*Start, error) {
 	return &Start{
 		credits: credits,
 		controls: controls,
+		rebind: rebinder,
+		stats: stats,
+		achievements: achievements,
 		menu: mainMenu,
 		backMap: backMap,
 	}, nil
@@ -122,6 +185,12 @@ func (self *Start) Update(ctx *context.Context) (*scene.Change, error) {
 		self.credits.Update(ctx)
 	} else if self.controls.IsVisible() {
 		self.controls.Update(ctx)
+	} else if self.rebind.IsVisible() {
+		self.rebind.Update(ctx)
+	} else if self.stats.IsVisible() {
+		self.stats.Update(ctx)
+	} else if self.achievements.IsVisible() {
+		self.achievements.Update(ctx)
 	} else {
 		change, err := self.menu.Update(ctx)
 		if change == nil && self.menu.Key() == keyLvlSel {
@@ -150,9 +219,9 @@ func (self *Start) DrawLogical(canvas *ebiten.Image, foremost bool, ctx *context
 	ctx.State.Editing = false
 
 	ctx.Background.DrawLogical(canvas, ctx)
-	self.backMap.DrawBackLogical(canvas, ctx, nil)
-	self.backMap.DrawMainLogical(canvas, ctx, nil)
-	self.backMap.DrawFrontLogical(canvas, ctx, nil)
+	self.backMapCache.DrawBackLogical(canvas, ctx, self.backMap, nil)
+	self.backMapCache.DrawMainLogical(canvas, ctx, self.backMap, nil)
+	self.backMapCache.DrawFrontLogical(canvas, ctx, self.backMap, nil)
 	
 	// draw game title (white part behind, black part in front)
 	white := color.RGBA{244, 244, 244, 244} // slightly translucid
@@ -174,6 +243,12 @@ func (self *Start) DrawLogical(canvas *ebiten.Image, foremost bool, ctx *context
 			self.controls.SetContent(info.ControlsKB)
 		}
 		self.controls.Draw(canvas)
+	} else if self.rebind.IsVisible() {
+		self.rebind.Draw(canvas, ctx)
+	} else if self.stats.IsVisible() {
+		self.stats.Draw(canvas)
+	} else if self.achievements.IsVisible() {
+		self.achievements.Draw(canvas)
 	} else {
 		self.menu.DrawLogical(canvas, ctx)
 	}
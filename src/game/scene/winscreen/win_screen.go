@@ -1,5 +1,7 @@
 package winscreen
 
+import "fmt"
+import "errors"
 import "image/color"
 
 import "github.com/hajimehoshi/ebiten/v2"
@@ -11,6 +13,7 @@ import "github.com/tinne26/luckyfeet/src/game/context"
 import "github.com/tinne26/luckyfeet/src/game/utils"
 import "github.com/tinne26/luckyfeet/src/game/components/info"
 import "github.com/tinne26/luckyfeet/src/game/components/menu"
+import "github.com/tinne26/luckyfeet/src/game/leaderboard"
 import "github.com/tinne26/luckyfeet/src/game/material/scene/keys"
 
 var _ scene.Scene[*context.Context] = (*WinScreen)(nil)
@@ -18,6 +21,8 @@ var _ scene.Scene[*context.Context] = (*WinScreen)(nil)
 type WinScreen struct {
 	credits *info.Layer
 	controls *info.Layer
+	runStats *info.Layer
+	leaderboard *info.Layer
 	menu menu.Menu
 	pendingTransition bool
 }
@@ -36,9 +41,18 @@ func New(ctx *context.Context) (*WinScreen, error) {
 		"GITHUB.COM/TINNE26/LUCKYFEET",
 	})
 	win.controls = info.New(info.ControlsKB)
+	win.runStats = info.New(nil)
+	win.leaderboard = info.New(nil)
 
 	opts := win.menu.NewOptionList(keyMainMenu)
 	opts.Add(win.credits.NewOption("CREDITS"))
+	if ctx.State.LastRunStats != nil {
+		win.runStats.SetContent(info.RunStats(ctx.State.LastRunStats))
+		opts.Add(win.runStats.NewOption("RUN STATS"))
+	}
+	if ctx.State.Submission != nil {
+		opts.Add(win.leaderboard.NewOption("LEADERBOARD"))
+	}
 	opts.Add(&menu.NavOption{ Label: "OPTIONS", To: menu.Options })
 	opts.Add(&menu.SceneChangeOption{ Label: "BACK TO TITLE", Change: *scene.Pop() })
 	win.menu.NewGameOptionsOptionList(ctx)
@@ -57,11 +71,20 @@ func (self *WinScreen) Update(ctx *context.Context) (*scene.Change, error) {
 	// update background animation
 	ctx.Background.Update()
 	
+	// refresh leaderboard results, which arrive in the background
+	if ctx.State.Submission != nil {
+		self.leaderboard.SetContent(leaderboardLines(ctx))
+	}
+
 	// update menu or info layers
 	if self.credits.IsVisible() {
 		self.credits.Update(ctx)
 	} else if self.controls.IsVisible() {
 		self.controls.Update(ctx)
+	} else if self.runStats.IsVisible() {
+		self.runStats.Update(ctx)
+	} else if self.leaderboard.IsVisible() {
+		self.leaderboard.Update(ctx)
 	} else {
 		change, err := self.menu.Update(ctx)
 		if change != nil { self.menu.JumpTo(keyMainMenu) }
@@ -86,6 +109,16 @@ func (self *WinScreen) DrawLogical(canvas *ebiten.Image, foremost bool, ctx *con
 	strs := []string{ "CLEARED IN " + utils.FmtTicksToTimeStrCents(ctx.State.LastClearTicks) }
 	text.CenterDrawAt(canvas, x, y - 4, strs, white, 4)
 	text.CenterDrawAt(canvas, x, y - 0, strs, black, 4)
+
+	// draw personal best comparison and leaderboard status
+	lineY := y + 30
+	for _, str := range []string{ pbComparisonStr(ctx), leaderboardStatusStr(ctx) } {
+		if str == "" { continue }
+		strs = []string{ str }
+		text.CenterDrawAt(canvas, x, lineY + 0, strs, white, 2)
+		text.CenterDrawAt(canvas, x, lineY + 2, strs, black, 2)
+		lineY += 20
+	}
 	
 	// draw menu or info layer
 	if self.credits.IsVisible() {
@@ -97,11 +130,57 @@ func (self *WinScreen) DrawLogical(canvas *ebiten.Image, foremost bool, ctx *con
 			self.controls.SetContent(info.ControlsKB)
 		}
 		self.controls.Draw(canvas)
+	} else if self.runStats.IsVisible() {
+		self.runStats.Draw(canvas)
+	} else if self.leaderboard.IsVisible() {
+		self.leaderboard.Draw(canvas)
 	} else {
 		self.menu.DrawLogical(canvas, ctx)
 	}
 }
 
+func pbComparisonStr(ctx *context.Context) string {
+	best := ctx.State.LastBestTicks
+	if best == 0 {
+		if ctx.State.LastClearIsPB { return "NEW PERSONAL BEST!" }
+		return ""
+	}
+	delta := utils.FmtTicksToDeltaStrCents(ctx.State.LastClearTicks - best)
+	if ctx.State.LastClearIsPB {
+		return "NEW PERSONAL BEST! (" + delta + ")"
+	}
+	return "PERSONAL BEST " + utils.FmtTicksToTimeStrCents(best) + " (" + delta + ")"
+}
+
+func leaderboardStatusStr(ctx *context.Context) string {
+	if ctx.State.Submission == nil { return "" }
+	entry, _, done, err := ctx.State.Submission.Result()
+	switch {
+	case !done:
+		return "SUBMITTING TO LEADERBOARD..."
+	case errors.Is(err, leaderboard.ErrRejected):
+		return "RUN REJECTED BY LEADERBOARD"
+	case err != nil:
+		return "LEADERBOARD UNAVAILABLE"
+	case entry.Rank == 0:
+		return "NOT IN THE LEADERBOARD TOP"
+	default:
+		return fmt.Sprintf("LEADERBOARD RANK #%d", entry.Rank)
+	}
+}
+
+func leaderboardLines(ctx *context.Context) []string {
+	_, top, done, err := ctx.State.Submission.Result()
+	if !done || err != nil { return []string{ leaderboardStatusStr(ctx) } }
+	if len(top) == 0 { return []string{ "NO LEADERBOARD ENTRIES YET" } }
+
+	lines := []string{ "LEADERBOARD", "" }
+	for _, entry := range top {
+		lines = append(lines, fmt.Sprintf("%d. %s %s", entry.Rank, entry.Name, utils.FmtTicksToTimeStrCents(entry.ClearTicks)))
+	}
+	return lines
+}
+
 func (self *WinScreen) DrawHiRes(canvas *ebiten.Image, foremost bool, ctx *context.Context) {
 	// ...
 }
@@ -0,0 +1,103 @@
+package settings
+
+import "fmt"
+import "bufio"
+import "bytes"
+import "errors"
+import "strconv"
+import "strings"
+import "io/fs"
+
+import "github.com/tinne26/luckyfeet/src/game/storage"
+
+// Storage name for the settings.
+const FileName = "settings.txt"
+
+const headerPrefix = "luckyfeet settings v"
+const version = 1
+
+var ErrInvalidData = errors.New("invalid settings data")
+
+// Loads the settings from storage. If nothing was saved yet,
+// defaults are returned.
+func Load() (*Settings, error) {
+	data, err := storage.Load(FileName)
+	if errors.Is(err, fs.ErrNotExist) { return New(), nil }
+	if err != nil { return nil, err }
+	return Decode(data)
+}
+
+func (self *Settings) Save() error {
+	return storage.Save(FileName, self.Encode())
+}
+
+// Encodes the settings as text, one "<name> <value>" per line.
+func (self *Settings) Encode() []byte {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "%s%d\n", headerPrefix, version)
+	fmt.Fprintf(&buffer, "music %d\nsfx %d\n", self.MusicLevel, self.SfxLevel)
+	fmt.Fprintf(&buffer, "music_muted %t\nsfx_muted %t\n", self.MusicMuted, self.SfxMuted)
+	fmt.Fprintf(&buffer, "screen_fit %d\nwin_resize %t\n", self.ScreenFit, self.AllowWinResize)
+	fmt.Fprintf(&buffer, "stick_deadzone %d\n", self.StickDeadzone)
+	fmt.Fprintf(&buffer, "hide_ghost %t\nsplit_timer %t\n", self.HideGhost, self.SplitTimer)
+	fmt.Fprintf(&buffer, "show_splits %t\nsplit_comparison %d\n", self.ShowSplits, self.SplitComparison)
+	fmt.Fprintf(&buffer, "show_fps %t\n", self.ShowFPS)
+	return buffer.Bytes()
+}
+
+// Parsing is tolerant: unknown names and invalid values are
+// skipped, keeping their defaults, so a damaged file or one
+// saved by another version loses as little as possible. Only
+// data that isn't a settings file at all fails.
+func Decode(data []byte) (*Settings, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() { return nil, ErrInvalidData }
+	header := scanner.Text()
+	if !strings.HasPrefix(header, headerPrefix) { return nil, ErrInvalidData }
+	_, err := strconv.Atoi(header[len(headerPrefix) : ]) // any version, see above
+	if err != nil { return nil, ErrInvalidData }
+
+	settings := New()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 { continue }
+		value := fields[1]
+		switch fields[0] {
+		case "music": parseLevel(value, &settings.MusicLevel)
+		case "sfx"  : parseLevel(value, &settings.SfxLevel)
+		case "music_muted": parseBool(value, &settings.MusicMuted)
+		case "sfx_muted"  : parseBool(value, &settings.SfxMuted)
+		case "screen_fit":
+			mode, err := strconv.ParseUint(value, 10, 8)
+			if err == nil && ScreenFitMode(mode) <= ScreenFitStretch {
+				settings.ScreenFit = ScreenFitMode(mode)
+			}
+		case "win_resize" : parseBool(value, &settings.AllowWinResize)
+		case "stick_deadzone":
+			deadzone, err := strconv.ParseUint(value, 10, 8)
+			if err == nil && deadzone <= MaxStickDeadzone {
+				settings.StickDeadzone = uint8(deadzone)
+			}
+		case "hide_ghost" : parseBool(value, &settings.HideGhost)
+		case "split_timer": parseBool(value, &settings.SplitTimer)
+		case "show_splits": parseBool(value, &settings.ShowSplits)
+		case "split_comparison":
+			comparison, err := strconv.ParseUint(value, 10, 8)
+			if err == nil && SplitComparison(comparison) <= CompareBestSegments {
+				settings.SplitComparison = SplitComparison(comparison)
+			}
+		case "show_fps": parseBool(value, &settings.ShowFPS)
+		}
+	}
+	return settings, nil
+}
+
+func parseLevel(value string, target *uint8) {
+	level, err := strconv.ParseUint(value, 10, 8)
+	if err == nil && level <= 100 { *target = uint8(level) }
+}
+
+func parseBool(value string, target *bool) {
+	flag, err := strconv.ParseBool(value)
+	if err == nil { *target = flag }
+}
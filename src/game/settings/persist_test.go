@@ -0,0 +1,36 @@
+package settings
+
+import "testing"
+
+func TestEncode(t *testing.T) {
+	settings := New()
+	settings.MusicLevel, settings.SfxMuted = 35, true
+	settings.ScreenFit = ScreenFitStretch
+	settings.SplitTimer, settings.SplitComparison = true, CompareBestSegments
+	settings.ShowFPS = true
+	settings.StickDeadzone = 35
+
+	decoded, err := Decode(settings.Encode())
+	if err != nil { t.Fatal(err) }
+	if *decoded != *settings {
+		t.Fatalf("expected %+v, got %+v", settings, decoded)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	// unknown names and invalid values keep the defaults
+	data := "luckyfeet settings v7\nmusic 20\nsfx 101\nscreen_fit 9\n\nsfx_muted yes please\nvsync true\nshow_fps 1\nstick_deadzone 96\n"
+	decoded, err := Decode([]byte(data))
+	if err != nil { t.Fatal(err) }
+	expected := New()
+	expected.MusicLevel, expected.ShowFPS = 20, true
+	if *decoded != *expected {
+		t.Fatalf("expected %+v, got %+v", expected, decoded)
+	}
+
+	invalid := []string{ "", "music 20\n", "luckyfeet settings vX\n", "luckyfeet records v1\n" }
+	for _, data := range invalid {
+		_, err := Decode([]byte(data))
+		if err == nil { t.Fatalf("expected error for %q", data) }
+	}
+}
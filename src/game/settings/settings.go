@@ -14,13 +14,26 @@ type Settings struct {
 	ScreenFit ScreenFitMode
 	AllowWinResize bool // ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 
+	// input
+	StickDeadzone uint8 // uses 0 - MaxStickDeadzone range, percent of the stick range
+
+	// gameplay
+	HideGhost bool // ghost of the best run for the current level
+	SplitTimer bool // show centiseconds, segments and deltas on races
+	ShowSplits bool // expanded split list, only for the split timer
+	SplitComparison SplitComparison
+
 	// debug and performance
 	ShowFPS bool
 }
 
 func New() *Settings {
 	return &Settings{
-		MusicLevel: 50,
-		SfxLevel: 50,
+		MusicLevel: 70,
+		SfxLevel: 70,
+		StickDeadzone: 20,
 	}
 }
+
+// Larger deadzones would leave the sticks barely usable.
+const MaxStickDeadzone = 95
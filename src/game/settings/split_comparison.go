@@ -0,0 +1,9 @@
+package settings
+
+// What the split timer compares the current run against.
+type SplitComparison uint8
+
+const (
+	CompareBestRun      SplitComparison = 0 // splits of the personal best
+	CompareBestSegments SplitComparison = 1 // sum of the best segments (golds)
+)
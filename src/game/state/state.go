@@ -1,16 +1,43 @@
 package state
 
 import "github.com/tinne26/luckyfeet/src/game/material/level"
+import "github.com/tinne26/luckyfeet/src/game/replay"
+import "github.com/tinne26/luckyfeet/src/game/ghost"
+import "github.com/tinne26/luckyfeet/src/game/records"
+import "github.com/tinne26/luckyfeet/src/game/leaderboard"
+import "github.com/tinne26/luckyfeet/src/game/stats"
+import "github.com/tinne26/luckyfeet/src/game/achievements"
+import "github.com/tinne26/luckyfeet/src/game/player/physics"
 
 type State[Context any] struct {
 	LoadMapDataFromClipboard bool
 	PlaytestData string
 	PlaytestMapID uint8
+	PlaytestOverrides map[uint8]physics.Overrides // saved while playtesting, by map ID
 	LevelKey level.Key
+	CharacterIndex int // index into Context.Characters
 	Editing bool
 	LastClearTicks int
+	LastBestTicks int // best before the last clear, 0 if none or not recorded
+	LastClearIsPB bool
+	Records *records.Records // persistent personal bests
+	Stats *stats.Lifetime // persistent totals over all runs
+	LastRunStats *stats.Run
+	Achievements *achievements.Unlocked // persistent
+	Replay *replay.Replay // last recorded or loaded run
+	WatchReplay bool // if true, the next Play scene plays Replay back
+	Ghosts map[records.Key]*ghost.Run // persistent best runs, by level pack hash and start map
+	Leaderboard leaderboard.Client // nil if disabled
+	PlayerName string // name for leaderboard submissions
+	Submission *leaderboard.Submission // for the last clear, nil if not submitted
 }
 
 func New[Context any]() *State[Context] {
-	return &State[Context]{}
+	return &State[Context]{
+		Ghosts: make(map[records.Key]*ghost.Run),
+		Records: records.New(),
+		Stats: stats.NewLifetime(),
+		Achievements: achievements.New(),
+		PlayerName: "ANONYMOUS",
+	}
 }
@@ -0,0 +1,187 @@
+package stats
+
+import "fmt"
+import "sort"
+import "bufio"
+import "bytes"
+import "errors"
+import "strconv"
+import "strings"
+import "io/fs"
+
+import "github.com/tinne26/luckyfeet/src/game/storage"
+
+// Storage name for the lifetime stats.
+const FileName = "stats.txt"
+
+const header = "luckyfeet stats v1"
+
+var ErrInvalidData = errors.New("invalid stats data")
+
+// Gameplay counters shared by runs and lifetime totals.
+type Counters struct {
+	Jumps int // including slip jumps and wall jumps, but not tic-tacs
+	TicTacs int
+	Falls int
+	Transfers int
+	Collected [3]int // orange, yellow and purple carrots
+	Eaten [3]int
+}
+
+// Varieties are given as carrot.Variety values. This package can't
+// import carrot, as carrot drawing needs the game context.
+func (self *Counters) Collect(variety uint8) { self.Collected[variety - 1] += 1 }
+func (self *Counters) Eat(variety uint8) { self.Eaten[variety - 1] += 1 }
+
+func (self *Counters) add(other *Counters) {
+	self.Jumps += other.Jumps
+	self.TicTacs += other.TicTacs
+	self.Falls += other.Falls
+	self.Transfers += other.Transfers
+	for i, _ := range self.Collected {
+		self.Collected[i] += other.Collected[i]
+		self.Eaten[i] += other.Eaten[i]
+	}
+}
+
+// Stats of a single run.
+type Run struct {
+	Counters
+	MapTicks []int // time spent on each map of the level pack, by map index
+}
+
+func NewRun(numMaps int) *Run {
+	return &Run{ MapTicks: make([]int, numMaps) }
+}
+
+// Maps are identified by level pack hash and map index.
+type MapKey struct {
+	PackHash uint64
+	MapIndex uint8
+}
+
+// Stats accumulated over all runs.
+type Lifetime struct {
+	Counters
+	Runs int // cleared or exited
+	Clears int
+	MapTicks map[MapKey]int
+}
+
+func NewLifetime() *Lifetime {
+	return &Lifetime{ MapTicks: make(map[MapKey]int) }
+}
+
+func (self *Lifetime) Add(run *Run, packHash uint64, cleared bool) {
+	self.Counters.add(&run.Counters)
+	self.Runs += 1
+	if cleared { self.Clears += 1 }
+	for i, ticks := range run.MapTicks {
+		if ticks == 0 { continue }
+		self.MapTicks[MapKey{ PackHash: packHash, MapIndex: uint8(i) }] += ticks
+	}
+}
+
+// Returns the total time spent on the maps of the given level pack.
+func (self *Lifetime) PackTicks(packHash uint64) int {
+	var total int
+	for key, ticks := range self.MapTicks {
+		if key.PackHash == packHash { total += ticks }
+	}
+	return total
+}
+
+// Returns the total time spent on all maps.
+func (self *Lifetime) TotalTicks() int {
+	var total int
+	for _, ticks := range self.MapTicks { total += ticks }
+	return total
+}
+
+// Loads the lifetime stats from storage. If nothing was saved yet,
+// empty stats are returned.
+func Load() (*Lifetime, error) {
+	data, err := storage.Load(FileName)
+	if errors.Is(err, fs.ErrNotExist) { return NewLifetime(), nil }
+	if err != nil { return nil, err }
+	return Decode(data)
+}
+
+func (self *Lifetime) Save() error {
+	return storage.Save(FileName, self.Encode())
+}
+
+// Encodes the stats as text, with one "<name> <values...>" entry
+// per line and one "map <pack hash> <map index> <ticks>" line per
+// map played.
+func (self *Lifetime) Encode() []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString(header + "\n")
+	fmt.Fprintf(&buffer, "runs %d\nclears %d\n", self.Runs, self.Clears)
+	fmt.Fprintf(&buffer, "jumps %d\ntictacs %d\n", self.Jumps, self.TicTacs)
+	fmt.Fprintf(&buffer, "falls %d\ntransfers %d\n", self.Falls, self.Transfers)
+	fmt.Fprintf(&buffer, "collected %d %d %d\n", self.Collected[0], self.Collected[1], self.Collected[2])
+	fmt.Fprintf(&buffer, "eaten %d %d %d\n", self.Eaten[0], self.Eaten[1], self.Eaten[2])
+
+	keys := make([]MapKey, 0, len(self.MapTicks))
+	for key, _ := range self.MapTicks { keys = append(keys, key) }
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].PackHash != keys[j].PackHash {
+			return keys[i].PackHash < keys[j].PackHash
+		}
+		return keys[i].MapIndex < keys[j].MapIndex
+	})
+	for _, key := range keys {
+		fmt.Fprintf(&buffer, "map %016x %d %d\n", key.PackHash, key.MapIndex, self.MapTicks[key])
+	}
+	return buffer.Bytes()
+}
+
+// Unknown entries are ignored, so stats saved by newer versions
+// can still be read.
+func Decode(data []byte) (*Lifetime, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() || scanner.Text() != header { return nil, ErrInvalidData }
+
+	lifetime := NewLifetime()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 { continue }
+		var err error
+		switch fields[0] {
+		case "runs"     : err = parseInts(fields[1 : ], &lifetime.Runs)
+		case "clears"   : err = parseInts(fields[1 : ], &lifetime.Clears)
+		case "jumps"    : err = parseInts(fields[1 : ], &lifetime.Jumps)
+		case "tictacs"  : err = parseInts(fields[1 : ], &lifetime.TicTacs)
+		case "falls"    : err = parseInts(fields[1 : ], &lifetime.Falls)
+		case "transfers": err = parseInts(fields[1 : ], &lifetime.Transfers)
+		case "collected":
+			counts := &lifetime.Collected
+			err = parseInts(fields[1 : ], &counts[0], &counts[1], &counts[2])
+		case "eaten":
+			counts := &lifetime.Eaten
+			err = parseInts(fields[1 : ], &counts[0], &counts[1], &counts[2])
+		case "map":
+			if len(fields) != 4 { return nil, ErrInvalidData }
+			hash, hashErr := strconv.ParseUint(fields[1], 16, 64)
+			mapIndex, indexErr := strconv.ParseUint(fields[2], 10, 8)
+			if hashErr != nil || indexErr != nil { return nil, ErrInvalidData }
+			var ticks int
+			err = parseInts(fields[3 : ], &ticks)
+			lifetime.MapTicks[MapKey{ PackHash: hash, MapIndex: uint8(mapIndex) }] = ticks
+		}
+		if err != nil { return nil, err }
+	}
+	if scanner.Err() != nil { return nil, ErrInvalidData }
+	return lifetime, nil
+}
+
+func parseInts(fields []string, targets ...*int) error {
+	if len(fields) != len(targets) { return ErrInvalidData }
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil || value < 0 { return ErrInvalidData }
+		*targets[i] = value
+	}
+	return nil
+}
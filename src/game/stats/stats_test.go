@@ -0,0 +1,54 @@
+package stats
+
+import "reflect"
+import "testing"
+
+func TestLifetime(t *testing.T) {
+	run := NewRun(3)
+	run.Jumps, run.TicTacs, run.Falls, run.Transfers = 10, 2, 1, 2
+	run.Collect(1) // orange
+	run.Collect(3) // purple
+	run.Eat(3)
+	run.MapTicks[0], run.MapTicks[2] = 300, 150
+
+	lifetime := NewLifetime()
+	lifetime.Add(run, 7, true)
+	lifetime.Add(run, 7, false)
+	lifetime.Add(NewRun(1), 8, false)
+	if lifetime.Runs != 3 || lifetime.Clears != 1 || lifetime.Jumps != 20 || lifetime.TicTacs != 4 {
+		t.Fatalf("unexpected counters %+v", lifetime)
+	}
+	if lifetime.Collected != [3]int{ 2, 0, 2 } || lifetime.Eaten != [3]int{ 0, 0, 2 } {
+		t.Fatalf("unexpected carrots %v, %v", lifetime.Collected, lifetime.Eaten)
+	}
+	if lifetime.PackTicks(7) != 900 || lifetime.TotalTicks() != 900 || len(lifetime.MapTicks) != 2 {
+		t.Fatalf("unexpected map ticks %v", lifetime.MapTicks)
+	}
+
+	decoded, err := Decode(lifetime.Encode())
+	if err != nil { t.Fatal(err) }
+	if !reflect.DeepEqual(decoded, lifetime) {
+		t.Fatalf("expected %+v, got %+v", lifetime, decoded)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	// unknown entries from newer versions are ignored
+	decoded, err := Decode([]byte(header + "\njumps 5\nhops 3 4\n\nmap 00000000000000ff 1 20\n"))
+	if err != nil { t.Fatal(err) }
+	if decoded.Jumps != 5 || decoded.MapTicks[MapKey{ PackHash: 255, MapIndex: 1 }] != 20 {
+		t.Fatalf("unexpected stats %+v", decoded)
+	}
+
+	invalid := []string{
+		"",
+		"luckyfeet stats v0\n",
+		header + "\njumps -1\n",
+		header + "\ncollected 1 2\n",
+		header + "\nmap zz 1 20\n",
+	}
+	for _, data := range invalid {
+		_, err := Decode([]byte(data))
+		if err == nil { t.Fatalf("expected error for %q", data) }
+	}
+}
@@ -0,0 +1,25 @@
+package storage
+
+import "errors"
+
+// Suffix for the copies made by [SetAside].
+const BackupSuffix = ".bak"
+
+var ErrLocked = errors.New("saving disabled after a failed load")
+
+// Names that can't be saved for the rest of the session.
+var locked = make(map[string]bool)
+
+// Keeps data that can't be loaded (damaged, or written by a newer
+// version) from being lost once the defaults loaded in its place
+// get saved. The data is copied to name + BackupSuffix, or if that
+// fails, saving the name is disabled for the rest of the session.
+func SetAside(name string) error {
+	data, err := Load(name)
+	if err == nil { err = Save(name + BackupSuffix, data) }
+	if err != nil {
+		locked[name] = true
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+//go:build !wasm
+
+package storage
+
+import "os"
+import "path/filepath"
+
+// Directory name inside the user config directory.
+const appDir = "luckyfeet"
+
+// Loads the named data. If it was never saved, the returned
+// error matches fs.ErrNotExist.
+func Load(name string) ([]byte, error) {
+	path, err := pathFor(name)
+	if err != nil { return nil, err }
+	return os.ReadFile(path)
+}
+
+func Save(name string, data []byte) error {
+	if locked[name] { return ErrLocked }
+	path, err := pathFor(name)
+	if err != nil { return err }
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil { return err }
+	return os.WriteFile(path, data, 0644)
+}
+
+func pathFor(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil { return "", err }
+	return filepath.Join(dir, appDir, name), nil
+}
@@ -0,0 +1,35 @@
+//go:build wasm
+
+package storage
+
+import "io/fs"
+import "errors"
+import "syscall/js"
+import "encoding/base64"
+
+// Prefix for localStorage keys.
+const keyPrefix = "luckyfeet/"
+
+var errNoStorage = errors.New("localStorage is not available")
+var errStorageFailed = errors.New("localStorage write failed")
+
+// Loads the named data. If it was never saved, the returned
+// error matches fs.ErrNotExist.
+func Load(name string) ([]byte, error) {
+	localStorage := js.Global().Get("localStorage")
+	if localStorage.IsUndefined() { return nil, errNoStorage }
+	value := localStorage.Call("getItem", keyPrefix + name)
+	if value.IsNull() { return nil, fs.ErrNotExist }
+	return base64.StdEncoding.DecodeString(value.String())
+}
+
+func Save(name string, data []byte) (err error) {
+	if locked[name] { return ErrLocked }
+	localStorage := js.Global().Get("localStorage")
+	if localStorage.IsUndefined() { return errNoStorage }
+	defer func() { // setItem throws when the quota is exceeded
+		if r := recover(); r != nil { err = errStorageFailed }
+	}()
+	localStorage.Call("setItem", keyPrefix + name, base64.StdEncoding.EncodeToString(data))
+	return nil
+}
@@ -1,4 +1,4 @@
-//go:build !wasm
+//go:build !wasm && !headless
 
 // === IMPORTANT NOTICE ====================================================
 // This file has been shamelessly stolen from https://github.com/ketMix/retromancer.
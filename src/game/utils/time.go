@@ -20,6 +20,12 @@ func paddedSecs(secs float64) string {
 	}
 }
 
+// Like FmtTicksToTimeStrCents, but always signed.
+func FmtTicksToDeltaStrCents(ticks int) string {
+	if ticks < 0 { return "-" + FmtTicksToTimeStrCents(-ticks) }
+	return "+" + FmtTicksToTimeStrCents(ticks)
+}
+
 func FmtTicksToTimeStrSecs(ticks int) string {
 	secs := ticks/120.0
 	mins := secs/60.0
@@ -7,10 +7,9 @@ var _ KBGPLike = (*Gamepad)(nil)
 // Limitations:
 // - Only the first / "oldest" gamepad is used. The code could easily be
 //   adapted, but the UIs and the model are not so trivial to adjust.
-// - We are not really making use of analog values and joysticks yet.
+// - Only the left stick horizontal axis is exposed as an analog value.
 // - Vibration not exposed through anywhere, though that feels like it
 //   should be on a separate place, as it's not really input but feedback.
-// - Haven't created a scene for new gamepad interruption + config yet.
 // - Would be great to be able to export all the gamepad stuff to an
 //   external library, constants, screens, helpers, dat format, etc.
 
@@ -29,6 +28,9 @@ type Gamepad struct {
 	repeatNext int32
 	cachedDir Direction
 	cachedDir8 Direction
+	horzAxis float64 // left stick, with the deadzone already applied
+	axisDeadzone float64
+	horzAxisBlocked bool
 	dirUnifiedTrigger bool
 	isLayoutSet bool
 	isIdle bool
@@ -41,6 +43,7 @@ func NewGamepad() *Gamepad {
 		actionAccTicks: make(map[TriggerAction]int32, 8),
 		repeatFirst: pkgRepeatFirst,
 		repeatNext: pkgRepeatNext,
+		axisDeadzone: pkgAxisDeadzone,
 	}
 }
 
@@ -127,6 +130,18 @@ func (self *Gamepad) HorzDir() Direction {
 	}
 }
 
+// Returns the horizontal movement in [-1, 1]. Directional buttons
+// take precedence and always return -1 or 1, while the left stick
+// returns its deflection beyond the configured deadzone.
+func (self *Gamepad) HorzAxis() float64 {
+	switch self.HorzDir() {
+	case DirRight: return  1.0
+	case DirLeft : return -1.0
+	default:
+		return self.horzAxis
+	}
+}
+
 // All inputs are blocked (won't be triggered) until a
 // subsequent update determines that actions are not pressed
 // anymore. This is very helpful for scene transitions
@@ -141,6 +156,8 @@ func (self *Gamepad) Unwind() {
    }
 	self.dirUnifiedTicks = 0
 	self.dirUnifiedTrigger = false
+	self.horzAxis = 0
+	self.horzAxisBlocked = true
 }
 
 func (self *Gamepad) Zero() {
@@ -152,6 +169,8 @@ func (self *Gamepad) Zero() {
    }
 	self.dirUnifiedTicks = 0
 	self.dirUnifiedTrigger = false
+	self.horzAxis = 0
+	self.horzAxisBlocked = false
 }
 
 func (self *Gamepad) Update() error {
@@ -163,7 +182,7 @@ func (self *Gamepad) Update() error {
 	if hadGamepadID {
 		prevGamepadID = self.gamepadIds[0]
 	}
-	self.gamepadIds = ebiten.AppendGamepadIDs(self.gamepadIds)
+	self.gamepadIds = ebiten.AppendGamepadIDs(self.gamepadIds[ : 0])
 
 	// update gamepad status
 	if len(self.gamepadIds) == 0 {
@@ -226,6 +245,22 @@ func (self *Gamepad) Update() error {
 		self.dirUnifiedTicks = 0
 		self.dirUnifiedTrigger = false
 	}
+
+	// update analog horizontal axis
+	var rawHorzAxis float64
+	if self.isLayoutSet {
+		rawHorzAxis = self.currentLayout[GamepadLeftStickHorzAxis].Value(id)
+	} else if ebiten.IsStandardGamepadLayoutAvailable(id) {
+		rawHorzAxis = ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+	}
+	horzAxis := applyAxisDeadzone(rawHorzAxis, self.axisDeadzone)
+	if horzAxis == 0 {
+		self.horzAxisBlocked = false
+	} else if self.horzAxisBlocked {
+		horzAxis = 0
+	}
+	if horzAxis != self.horzAxis || horzAxis != 0 { self.isIdle = false }
+	self.horzAxis = horzAxis
 	
 	return nil
 }
@@ -261,6 +296,28 @@ func (self *Gamepad) updateDirWithoutLayout(i int, id ebiten.GamepadID, button G
 	return newestDirIndex, newestDirValue
 }
 
+// Returns the ID of the active gamepad, if any is connected.
+func (self *Gamepad) ActiveID() (ebiten.GamepadID, bool) {
+	if len(self.gamepadIds) == 0 { return 0, false }
+	return self.gamepadIds[0], true
+}
+
+// Appends the standard inputs currently pressed on the active
+// gamepad, axes excluded. Useful to capture inputs for rebinding.
+func (self *Gamepad) AppendPressedInputs(inputs []GamepadStandardInput) []GamepadStandardInput {
+	if len(self.gamepadIds) == 0 { return inputs }
+	id := self.gamepadIds[0]
+	for input := GamepadStandardInput(0); input < gamepadNumStandardInputs; input++ {
+		if input.IsAxis() { continue }
+		if self.isLayoutSet {
+			if self.currentLayout[input].Pressed(id) { inputs = append(inputs, input) }
+		} else if input.StdEquivalentPressed(id) {
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs
+}
+
 func (self *Gamepad) Config() *GamepadConfig {
 	return (*GamepadConfig)(self)
 }
@@ -0,0 +1,130 @@
+package input
+
+import "math"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// Axis thresholds for calibration, relative to the resting value.
+// Axes resting far from zero (like some analog triggers) can't be
+// used, as layout inputs consider them pressed beyond a fixed value.
+const (
+	calibrationAxisPress   = 0.6
+	calibrationAxisRelease = 0.3
+	calibrationAxisMaxRest = 0.15
+)
+
+// Layout inputs only have 6 bits for the index, and 0 is undefined.
+const calibrationMaxIndex = 62
+
+type CalibrationEvent uint8
+const (
+	CalibrationNone CalibrationEvent = iota
+	CalibrationAssigned
+	CalibrationSkipped
+)
+
+// Builds a [GamepadLayout] for a gamepad without standard mapping
+// by asking for each [GamepadStandardInput] in order and detecting
+// which raw button or axis gets pressed. Pressing an input that
+// was already assigned skips the current one.
+type GamepadCalibrator struct {
+	id ebiten.GamepadID
+	guid GamepadGUID
+	layout GamepadLayout
+	next GamepadStandardInput
+	restAxes []float64
+	waitingRelease bool
+}
+
+// The gamepad must be at rest when the calibrator is created, as
+// initial axis values are taken as resting values.
+func NewGamepadCalibrator(id ebiten.GamepadID) (*GamepadCalibrator, error) {
+	guid, err := StringToGamepadGUID(ebiten.GamepadSDLID(id))
+	if err != nil { return nil, err }
+	restAxes := make([]float64, min(ebiten.GamepadAxisCount(id), calibrationMaxIndex + 1))
+	for i, _ := range restAxes {
+		restAxes[i] = ebiten.GamepadAxisValue(id, i)
+	}
+	return &GamepadCalibrator{
+		id: id,
+		guid: guid,
+		restAxes: restAxes,
+		waitingRelease: true,
+	}, nil
+}
+
+func (self *GamepadCalibrator) GUID() GamepadGUID { return self.guid }
+func (self *GamepadCalibrator) Layout() GamepadLayout { return self.layout }
+func (self *GamepadCalibrator) Done() bool { return self.next >= gamepadNumStandardInputs }
+
+// Returns the standard input being calibrated. Invalid once done.
+func (self *GamepadCalibrator) Current() GamepadStandardInput {
+	return self.next
+}
+
+// Leaves the current input undefined and moves to the next one.
+func (self *GamepadCalibrator) Skip() {
+	if self.Done() { return }
+	self.layout[self.next] = 0
+	self.next += 1
+	self.waitingRelease = true
+}
+
+func (self *GamepadCalibrator) Update() CalibrationEvent {
+	if self.Done() { return CalibrationNone }
+	input, anyHeld := self.detect()
+	if self.waitingRelease {
+		if !anyHeld { self.waitingRelease = false }
+		return CalibrationNone
+	}
+	if input.Index() == -1 { return CalibrationNone }
+
+	if self.isAssigned(input) {
+		self.Skip()
+		return CalibrationSkipped
+	}
+	self.layout[self.next] = input
+	self.next += 1
+	self.waitingRelease = true
+	return CalibrationAssigned
+}
+
+// Returns the first input pressed that can be assigned to the
+// current standard input (undefined if none), and whether any
+// button or axis is held at all.
+func (self *GamepadCalibrator) detect() (GamepadLayoutInput, bool) {
+	var input GamepadLayoutInput
+	var anyHeld bool
+	wantsAxis := self.next.IsAxis()
+
+	numButtons := min(ebiten.GamepadButtonCount(self.id), calibrationMaxIndex + 1)
+	for i := 0; i < numButtons; i++ {
+		if !ebiten.IsGamepadButtonPressed(self.id, ebiten.GamepadButton(i)) { continue }
+		anyHeld = true
+		if !wantsAxis && input == 0 { input = NewGamepadBinaryButtonLayoutInput(i) }
+	}
+
+	for i, rest := range self.restAxes {
+		delta := math.Abs(ebiten.GamepadAxisValue(self.id, i) - rest)
+		if delta >= calibrationAxisRelease { anyHeld = true }
+		if delta < calibrationAxisPress || math.Abs(rest) > calibrationAxisMaxRest { continue }
+		if input != 0 { continue } // buttons take priority
+		if wantsAxis {
+			input = NewGamepadAxisLayoutInput(i)
+		} else {
+			input = NewGamepadAnalogButtonLayoutInput(i)
+		}
+	}
+	return input, anyHeld
+}
+
+// Axes and analog buttons share indices, as both read axes.
+func (self *GamepadCalibrator) isAssigned(input GamepadLayoutInput) bool {
+	for i := GamepadStandardInput(0); i < self.next; i++ {
+		assigned := self.layout[i]
+		if assigned.Index() == input.Index() && assigned.IsAnalog() == input.IsAnalog() {
+			return true
+		}
+	}
+	return false
+}
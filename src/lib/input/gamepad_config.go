@@ -13,6 +13,11 @@ func (self *GamepadConfig) SetLayouts(knownLayouts map[GamepadGUID]GamepadLayout
 	(*Gamepad)(self).tryConfigureNewID(self.gamepadIds[0])
 }
 
+// Returns the known layouts, or nil if they were never set.
+func (self *GamepadConfig) GetLayouts() map[GamepadGUID]GamepadLayout {
+	return self.knownLayouts
+}
+
 // Equivalent to [*GamepadConfig.MapTriggerAction](action, nil).
 func (self *GamepadConfig) DeleteTriggerAction(action TriggerAction) {
 	self.MapTriggerAction(action, nil)
@@ -69,3 +74,17 @@ func (self *GamepadConfig) SetRepeatRate(repeatFirst int32, repeatNext int32) {
 func (self *GamepadConfig) SetDirButtons(dirButtons GamepadDirButtons) {
 	self.dirButtons = dirButtons
 }
+
+func (self *GamepadConfig) GetDirButtons() GamepadDirButtons {
+	return self.dirButtons
+}
+
+// Stick values below the deadzone are reported as zero, and the
+// remaining range is rescaled to [0, 1]. Defaults to 0.2.
+func (self *GamepadConfig) SetAxisDeadzone(deadzone float64) {
+	self.axisDeadzone = min(max(deadzone, 0), 0.95)
+}
+
+func (self *GamepadConfig) GetAxisDeadzone() float64 {
+	return self.axisDeadzone
+}
@@ -324,5 +324,6 @@ func (self *Gamepad) tryConfigureNewID(id ebiten.GamepadID) {
 	layout, hasLayout := self.knownLayouts[guid]
 	if !hasLayout { return }
 	self.currentLayout = layout
+	self.isLayoutSet = true
 	self.status |= gamepadStatusConfiguredFlag
 }
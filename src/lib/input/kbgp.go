@@ -116,6 +116,19 @@ func (self *KBGP) HorzDir() Direction {
 	}
 }
 
+// Returns the horizontal movement in [-1, 1]. Keyboard and
+// directional buttons are digital, only sticks return values
+// in between.
+func (self *KBGP) HorzAxis() float64 {
+	if self.shouldPrioritizeKeyboardDir() {
+		switch self.keyboard.HorzDir() {
+		case DirRight: return  1.0
+		case DirLeft : return -1.0
+		}
+	}
+	return self.gamepad.HorzAxis()
+}
+
 func (self *KBGP) shouldPrioritizeKeyboardDir() bool {
 	if self.gamepad.dirUnifiedTicks  <= 0 { return true  }
 	if self.keyboard.dirUnifiedTicks <= 0 { return false }
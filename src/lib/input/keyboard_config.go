@@ -63,3 +63,7 @@ func (self *KeyboardConfig) SetRepeatRate(repeatFirst int32, repeatNext int32) {
 func (self *KeyboardConfig) SetDirTriggers(dirTriggers KeyboardDirTriggers) {
 	self.dirTriggers = dirTriggers
 }
+
+func (self *KeyboardConfig) GetDirTriggers() KeyboardDirTriggers {
+	return self.dirTriggers
+}
@@ -38,7 +38,7 @@ func (self SingleButton) Pressed(id ebiten.GamepadID, layout *GamepadLayout) boo
 	return GamepadStandardInput(self).StdEquivalentPressed(id)
 }
 func (self SingleButton) String() string {
-	return self.String()
+	return GamepadStandardInput(self).String()
 }
 func (self SingleButton) Inputs() []ButtonInput {
 	return []ButtonInput{
@@ -110,3 +110,38 @@ func (self MultiButton) String() string {
 func (self MultiButton) Inputs() []ButtonInput {
 	return self.ButtonInputs
 }
+
+// ---- ButtonList ----
+var _ GamepadTrigger = ButtonList{}
+type ButtonList []GamepadStandardInput
+
+// Accepts *any* of the given buttons as a trigger.
+func NewButtonList(buttons ...GamepadStandardInput) ButtonList {
+	return ButtonList(buttons)
+}
+
+func (self ButtonList) Pressed(id ebiten.GamepadID, layout *GamepadLayout) bool {
+	for _, button := range self {
+		if layout != nil {
+			if layout[button].Pressed(id) { return true }
+		} else if button.StdEquivalentPressed(id) {
+			return true
+		}
+	}
+	return false
+}
+func (self ButtonList) String() string {
+	var str strings.Builder
+	for i, button := range self {
+		if i != 0 { str.Write([]byte{'|'}) }
+		str.WriteString(button.String())
+	}
+	return str.String()
+}
+func (self ButtonList) Inputs() []ButtonInput {
+	var inputs []ButtonInput = make([]ButtonInput, len(self))
+	for i, button := range self {
+		inputs[i] = ButtonInput{ Button: button, Mode: InputModeOr }
+	}
+	return inputs
+}
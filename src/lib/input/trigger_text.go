@@ -0,0 +1,143 @@
+package input
+
+import "fmt"
+import "errors"
+import "strings"
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// Text encodings for triggers, so bindings can be saved and
+// edited by hand:
+//  - "none" for unassigned triggers.
+//  - "Space" for a single key or "GamepadButtonBottom" for a
+//    single button.
+//  - "Backspace|Escape" for key lists and button lists.
+//  - "Tab+!AltLeft" for multi keys and multi buttons, with '!'
+//    marking breakers.
+
+const unassignedText = "none"
+
+var ErrUnsupportedTrigger = errors.New("unsupported trigger type")
+
+func EncodeKeyboardTrigger(trigger KeyboardTrigger) (string, error) {
+	switch typedTrigger := trigger.(type) {
+	case nil, UnassignedKey:
+		return unassignedText, nil
+	case SingleKey:
+		return ebiten.Key(typedTrigger).String(), nil
+	case KeyList:
+		if len(typedTrigger) == 0 { return unassignedText, nil }
+		names := make([]string, len(typedTrigger))
+		for i, key := range typedTrigger { names[i] = key.String() }
+		return strings.Join(names, "|"), nil
+	case MultiKey:
+		if len(typedTrigger.KeyInputs) == 0 { return unassignedText, nil }
+		names := make([]string, len(typedTrigger.KeyInputs))
+		for i, in := range typedTrigger.KeyInputs {
+			names[i] = in.Key.String()
+			if in.Mode == InputModeBreaker { names[i] = "!" + names[i] }
+		}
+		return strings.Join(names, "+"), nil
+	default:
+		return "", ErrUnsupportedTrigger
+	}
+}
+
+func ParseKeyboardTrigger(str string) (KeyboardTrigger, error) {
+	if str == unassignedText { return UnassignedKey{}, nil }
+	if strings.Contains(str, "|") {
+		var list KeyList
+		for _, name := range strings.Split(str, "|") {
+			key, err := parseKey(name)
+			if err != nil { return nil, err }
+			list = append(list, key)
+		}
+		return list, nil
+	}
+	if strings.Contains(str, "+") {
+		var multi MultiKey
+		for _, name := range strings.Split(str, "+") {
+			breakerName, isBreaker := strings.CutPrefix(name, "!")
+			key, err := parseKey(breakerName)
+			if err != nil { return nil, err }
+			if isBreaker {
+				multi.AddBreakerKey(key)
+			} else {
+				multi.AddNormalKey(key)
+			}
+		}
+		return multi, nil
+	}
+	key, err := parseKey(str)
+	if err != nil { return nil, err }
+	return SingleKey(key), nil
+}
+
+func parseKey(name string) (ebiten.Key, error) {
+	var key ebiten.Key
+	err := key.UnmarshalText([]byte(name))
+	if err != nil { return key, fmt.Errorf("invalid key '%s'", name) }
+	return key, nil
+}
+
+func EncodeGamepadTrigger(trigger GamepadTrigger) (string, error) {
+	switch typedTrigger := trigger.(type) {
+	case nil, UnassignedButton:
+		return unassignedText, nil
+	case SingleButton:
+		return GamepadStandardInput(typedTrigger).String(), nil
+	case ButtonList:
+		if len(typedTrigger) == 0 { return unassignedText, nil }
+		names := make([]string, len(typedTrigger))
+		for i, button := range typedTrigger { names[i] = button.String() }
+		return strings.Join(names, "|"), nil
+	case MultiButton:
+		if len(typedTrigger.ButtonInputs) == 0 { return unassignedText, nil }
+		names := make([]string, len(typedTrigger.ButtonInputs))
+		for i, in := range typedTrigger.ButtonInputs {
+			names[i] = in.Button.String()
+			if in.Mode == InputModeBreaker { names[i] = "!" + names[i] }
+		}
+		return strings.Join(names, "+"), nil
+	default:
+		return "", ErrUnsupportedTrigger
+	}
+}
+
+func ParseGamepadTrigger(str string) (GamepadTrigger, error) {
+	if str == unassignedText { return UnassignedButton{}, nil }
+	if strings.Contains(str, "|") {
+		var list ButtonList
+		for _, name := range strings.Split(str, "|") {
+			button, err := parseButton(name)
+			if err != nil { return nil, err }
+			list = append(list, button)
+		}
+		return list, nil
+	}
+	if strings.Contains(str, "+") {
+		var multi MultiButton
+		for _, name := range strings.Split(str, "+") {
+			breakerName, isBreaker := strings.CutPrefix(name, "!")
+			button, err := parseButton(breakerName)
+			if err != nil { return nil, err }
+			if isBreaker {
+				multi.AddBreaker(button)
+			} else {
+				multi.Add(button)
+			}
+		}
+		return multi, nil
+	}
+	button, err := parseButton(str)
+	if err != nil { return nil, err }
+	return SingleButton(button), nil
+}
+
+func parseButton(name string) (GamepadStandardInput, error) {
+	for button := GamepadStandardInput(0); button < gamepadNumStandardInputs; button++ {
+		if button.IsAxis() { continue }
+		if button.String() == name { return button, nil }
+	}
+	return 0, fmt.Errorf("invalid gamepad button '%s'", name)
+}
@@ -9,3 +9,14 @@ func uint8toBinaryStr(value uint8) string {
 	}
 	return string(bytes[:])
 }
+
+const pkgAxisDeadzone = 0.2
+
+// Maps the given axis value from [-1, 1] to the same range, but
+// returning zero within the deadzone and rescaling the rest so
+// there's no jump right after leaving it.
+func applyAxisDeadzone(value, deadzone float64) float64 {
+	if value < 0 { return -applyAxisDeadzone(-value, deadzone) }
+	if value <= deadzone { return 0 }
+	return min((value - deadzone)/(1.0 - deadzone), 1.0)
+}
@@ -22,6 +22,15 @@ func (self *Manager[Context]) FirstLoad(sceneKey Key, ctx Context) {
 	if len(self.Scenes) != 1 { panic("broken code") }
 }
 
+// Pushes a scene from outside the scenes themselves, for global
+// interruptions. Scene changes should be requested on Update instead
+// whenever possible.
+func (self *Manager[Context]) Push(sceneKey Key, ctx Context) {
+	if len(self.Scenes) == 0 { panic("scene manager has no scenes") }
+	newScene := self.Registry.MustLoad(sceneKey, ctx)
+	self.Scenes = append(self.Scenes, newScene)
+}
+
 func (self *Manager[Context]) Current() Scene[Context] {
 	if len(self.Scenes) == 0 { return nil }
 	return self.Scenes[len(self.Scenes) - 1]